@@ -0,0 +1,171 @@
+package bdsql
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// Sumar ejecuta una sentencia que obtiene la suma del campo (o expresión) en
+// los registros de la sentencia, utilizando las mismas tablas, juntas y
+// condiciones, y la asigna en el destino. Se aplican las mismas reglas que
+// en Maximo(): devuelve falso, sin modificar el destino, cuando no existen
+// registros o todos los valores son nulos.
+// La suma de una columna 'decimal' se obtiene como 'decimal': para no perder
+// precisión, el destino puede ser un texto (string) o un tipo que implemente
+// sql.Scanner.
+// En las sentencias agrupadas, el campo debe ser uno de los campos de la
+// sentencia: se suman los valores de todos los grupos.
+//
+//	var total string
+//	existe, err := bd.Seleccionar("-").Tabla("ventas").Sumar("importe", &total)
+func (o *seleccionar) Sumar(campo string, destino interface{}) (bool, error) {
+	return o.agregarEn("sum", campo, destino)
+}
+
+// Promediar ejecuta una sentencia que obtiene el promedio del campo (o
+// expresión) en los registros de la sentencia y lo asigna en el destino.
+// Se aplican las mismas reglas que en Sumar().
+func (o *seleccionar) Promediar(campo string, destino interface{}) (bool, error) {
+	return o.agregarEn("avg", campo, destino)
+}
+
+// Maximo ejecuta una sentencia que obtiene el valor máximo del campo (o
+// expresión) en los registros de la sentencia y lo asigna en el destino,
+// que debe ser un puntero de un tipo admitido en Resultado() (números,
+// textos, lógicos o fechas). Devuelve falso, sin modificar el destino,
+// cuando no existen registros o todos los valores son nulos.
+//
+//	var ultima time.Time
+//	existe, err := bd.Seleccionar("-").Tabla("ventas").Maximo("fecha", &ultima)
+func (o *seleccionar) Maximo(campo string, destino interface{}) (bool, error) {
+	return o.agregarEn("max", campo, destino)
+}
+
+// Minimo ejecuta una sentencia que obtiene el valor mínimo del campo (o
+// expresión) en los registros de la sentencia y lo asigna en el destino.
+// Se aplican las mismas reglas que en Maximo().
+func (o *seleccionar) Minimo(campo string, destino interface{}) (bool, error) {
+	return o.agregarEn("min", campo, destino)
+}
+
+// ResultadoMapa recibe el mapa donde se almacena el resultado de la
+// consulta: la sentencia debe obtener dos campos, la clave y el valor de
+// cada elemento del mapa. Se utiliza normalmente junto con AgruparPor().
+//
+//	var totales = map[string]float64{}
+//	cant, err := bd.
+//		Seleccionar("ventasPorZona").
+//		Tabla("ventas").
+//		Campos("zona", "sum(importe)").
+//		AgruparPor("zona").
+//		ResultadoMapa(&totales).
+//		Ejecutar()
+func (o *seleccionar) ResultadoMapa(mapa interface{}) *seleccionar {
+	o.objeto = mapa
+	return o
+}
+
+// agregarEn ejecuta la función de agregado y asigna el valor obtenido en el
+// destino.
+func (o *seleccionar) agregarEn(funcion, campo string, destino interface{}) (bool, error) {
+	var puntero = reflect.ValueOf(destino)
+	if puntero.Kind() != reflect.Ptr || puntero.IsNil() {
+		return false, errorNuevo().asignarMotivoSeleccionarTipoDeCampoIncorrecto()
+	}
+	asignar, err := funcionDeAsignacion(puntero.Elem().Type())
+	if err != nil {
+		return false, err
+	}
+
+	sentencia, valores, err := o.generarSQLAgregado(funcion, campo)
+	if err != nil {
+		return false, err
+	}
+	var valorCrudo interface{}
+	if err := o.consultarValor(sentencia, valores, &valorCrudo); err != nil {
+		return false, err
+	}
+	if valorCrudo == nil {
+		return false, nil
+	}
+
+	valor, err := asignar(valorCrudo, reflect.TypeOf(valorCrudo))
+	if err != nil {
+		return false, err
+	}
+	puntero.Elem().Set(valor.Convert(puntero.Elem().Type()))
+
+	return true, nil
+}
+
+// generarSQLAgregado devuelve la sentencia que aplica la función de agregado
+// al campo en los registros de la sentencia, y sus valores.
+func (o *seleccionar) generarSQLAgregado(funcion, campo string) (string, []interface{}, error) {
+	var c = o.sinOrden()
+	if c.esAgregada() {
+		subconsulta, valores, err := c.generarSQLSubconsulta()
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("select %v(t.%v) from (%v) as t;", funcion, campo, subconsulta), valores, nil
+	}
+
+	c.campos, c.camposSubconsultas, c.camposValores = []string{fmt.Sprintf("%v(%v)", funcion, campo)}, nil, nil
+	sentencia, err := c.generarSQL()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return expandirSentencia(sentencia, c.parametros())
+}
+
+// asignarAMapa asigna los registros obtenidos en el mapa recibido: el primer
+// campo de cada registro es la clave y el segundo es el valor.
+func asignarAMapa(filas *sql.Rows, objeto interface{}) (int, error) {
+	camposFila, err := filas.Columns()
+	if err != nil {
+		return 0, resolverErrorMysql(err)
+	}
+	if len(camposFila) != 2 {
+		return 0, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos(fmt.Sprintf("Se esperaban dos campos (clave y valor), se obtuvieron: %v", len(camposFila)))
+	}
+
+	var mapa = reflect.ValueOf(objeto).Elem()
+	if mapa.IsNil() {
+		mapa.Set(reflect.MakeMap(mapa.Type()))
+	}
+	var tipoClave, tipoValor = mapa.Type().Key(), mapa.Type().Elem()
+	asignarClave, err := funcionDeAsignacion(tipoClave)
+	if err != nil {
+		return 0, err
+	}
+	asignarValor, err := funcionDeAsignacion(tipoValor)
+	if err != nil {
+		return 0, err
+	}
+
+	var cant int
+	for filas.Next() {
+		cant++
+
+		var claveCruda, valorCrudo interface{}
+		if err := filas.Scan(&claveCruda, &valorCrudo); err != nil {
+			return 0, errorNuevo().asignarOrigen(err).asignarMotivoSeleccionarLecturaDeCampos()
+		}
+		clave, err := asignarClave(claveCruda, reflect.TypeOf(claveCruda))
+		if err != nil {
+			return 0, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos(fmt.Sprintf("Error: %v, Campo: %v", err, camposFila[0]))
+		}
+		valor, err := asignarValor(valorCrudo, reflect.TypeOf(valorCrudo))
+		if err != nil {
+			return 0, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos(fmt.Sprintf("Error: %v, Campo: %v", err, camposFila[1]))
+		}
+		mapa.SetMapIndex(clave.Convert(tipoClave), valor.Convert(tipoValor))
+	}
+	if err := filas.Err(); err != nil {
+		return 0, resolverErrorMysql(err)
+	}
+
+	return cant, nil
+}
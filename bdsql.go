@@ -1,204 +1,292 @@
-/*
-Package bdsql gestiona de manera simple, rápida y eficiente; las sentencias
-que se realizan con el motor de base de datos Mysql/MariaDB.*/
-package bdsql
-
-import (
-	"database/sql"
-	"sync"
-)
-
-// Conectar crea una conección con el motor de base de datos Mysql/MariaDB.
-func Conectar(dsn string, maxConAbiertas, maxConOciosas int) (*BD, error) {
-	db, err := sql.Open("mysql", dsn)
-	if err != nil {
-		return nil, errorNuevo().asignarOrigen(err).asignarMotivoConexionAbrir()
-	}
-	if err = db.Ping(); err != nil {
-		return nil, errorNuevo().asignarOrigen(err).asignarMotivoConexionAbrir()
-	}
-	db.SetMaxOpenConns(maxConAbiertas)
-	db.SetMaxIdleConns(maxConOciosas)
-
-	var bd = &BD{db: db}
-	bd.setencias = make(map[string]string)
-
-	return bd, nil
-}
-
-// BD representa el pool de conexiones con la base de datos.
-type BD struct {
-	db  *sql.DB    // manejador de la base de datos
-	mux sync.Mutex // bloqueador de exclusión mutua
-
-	// sentencias almacena sentencias SQL para que no vuelvan a
-	// ser generadas por cada llamada
-	setencias map[string]string
-}
-
-// Insertar representa la sentencia 'insert' de SQL.
-func (bd *BD) Insertar(nombre string) *insertar {
-	var o = &insertar{bd: bd}
-	if nombre != "-" {
-		o.senSQLNombre = nombre
-		o.senSQL, o.senSQLExiste = o.bd.obtenerSentenciaSQL(nombre)
-	}
-
-	return o
-}
-
-// Modificar representa la sentencia 'update' de SQL.
-func (bd *BD) Modificar(nombre string) *modificar {
-	var o = &modificar{bd: bd}
-	if nombre != "-" {
-		o.senSQLNombre = nombre
-		o.senSQL, o.senSQLExiste = o.bd.obtenerSentenciaSQL(nombre)
-	}
-
-	return o
-}
-
-// Eliminar representa la sentencia 'delete' de SQL.
-func (bd *BD) Eliminar(nombre string) *eliminar {
-	var o = &eliminar{bd: bd}
-	if nombre != "-" {
-		o.senSQLNombre = nombre
-		o.senSQL, o.senSQLExiste = o.bd.obtenerSentenciaSQL(nombre)
-	}
-
-	return o
-}
-
-// Seleccionar representa la sentencia 'select' de SQL.
-func (bd *BD) Seleccionar(nombre string) *seleccionar {
-	var o = &seleccionar{bd: bd}
-	if nombre != "-" {
-		o.senSQLNombre = nombre
-		o.senSQL, o.senSQLExiste = o.bd.obtenerSentenciaSQL(nombre)
-	}
-
-	return o
-}
-
-// TxIniciar inicia una nueva transacción.
-// Representa a la sentencia 'Begin' de SQL.
-func (bd *BD) TxIniciar() (*TX, error) {
-	txBD, err := bd.db.Begin()
-	if err != nil {
-		return nil, errorNuevo().asignarOrigen(err).asignarMotivoTxIniciar()
-	}
-
-	return &TX{bd: bd, tx: txBD}, nil
-}
-
-// Cerrar cierra la conexión con la base de datos.
-func (bd *BD) Cerrar() error {
-	if err := bd.db.Close(); err != nil {
-		return errorNuevo().asignarOrigen(err).asignarMotivoConexionCerrar()
-	}
-
-	return nil
-}
-
-// -----------------------------------------------------------------------------
-
-// TX representa a una transacción de la base de datos.
-// Es quien tiene la resposabilidad de mantener y otorgar
-// las sentencias a ejecutar de SQL dentro de una transacción.
-type TX struct {
-	bd *BD
-	tx *sql.Tx
-}
-
-// Insertar representa la sentencia 'insert' de SQL.
-func (tx *TX) Insertar(nombre string) *insertar {
-	var o = &insertar{bd: tx.bd, tx: tx.tx}
-	if nombre != "-" {
-		o.senSQLNombre = nombre
-		o.senSQL, o.senSQLExiste = o.bd.obtenerSentenciaSQL(nombre)
-	}
-
-	return o
-}
-
-// Modificar representa la sentencia 'update' de SQL.
-func (tx *TX) Modificar(nombre string) *modificar {
-	var o = &modificar{bd: tx.bd, tx: tx.tx}
-	if nombre != "-" {
-		o.senSQLNombre = nombre
-		o.senSQL, o.senSQLExiste = o.bd.obtenerSentenciaSQL(nombre)
-	}
-
-	return o
-}
-
-// Eliminar representa la sentencia 'delete' de SQL.
-func (tx *TX) Eliminar(nombre string) *eliminar {
-	var o = &eliminar{bd: tx.bd, tx: tx.tx}
-	if nombre != "-" {
-		o.senSQLNombre = nombre
-		o.senSQL, o.senSQLExiste = o.bd.obtenerSentenciaSQL(nombre)
-	}
-
-	return o
-}
-
-// Seleccionar representa la sentencia 'select' de SQL.
-func (tx *TX) Seleccionar(nombre string) *seleccionar {
-	var o = &seleccionar{bd: tx.bd, tx: tx.tx}
-	if nombre != "-" {
-		o.senSQLNombre = nombre
-		o.senSQL, o.senSQLExiste = o.bd.obtenerSentenciaSQL(nombre)
-	}
-
-	return o
-}
-
-// SeleccionarSql(sentencia string, valores ...interface{}) *seleccionarSql
-
-// TxConfirmar representa a la sentencia 'commit' de SQL.
-func (tx *TX) TxConfirmar() error {
-	if err := tx.tx.Commit(); err != nil {
-		return errorNuevo().asignarOrigen(err).asignarMotivoTxConfirmar()
-	}
-
-	return nil
-}
-
-// TxRevertir representa a la sentencia 'rollback' de SQL.
-func (tx *TX) TxRevertir() error {
-	if err := tx.tx.Rollback(); err != nil {
-		return errorNuevo().asignarOrigen(err).asignarMotivoTxRevertir()
-	}
-
-	return nil
-}
-
-// TxFinalizar decide si confirma o revierte la transacción
-// tomando en cuenta el error recibido.
-// Si es nulo, confirma la transacción (realiza 'commit').
-// En caso de recibir un error, revierte la transacción (realiza 'rollback').
-func (tx *TX) TxFinalizar(err error) error {
-	if err != nil {
-		return tx.TxRevertir()
-	}
-
-	return tx.TxConfirmar()
-}
-
-// -----------------------------------------------------------------------------
-// funciones internas
-
-func (bd *BD) obtenerSentenciaSQL(nombre string) (string, bool) {
-	bd.mux.Lock()
-	s, ok := bd.setencias[nombre]
-	bd.mux.Unlock()
-
-	return s, ok
-}
-
-func (bd *BD) guardarSentenciaSQL(nombre, sentenciaSQL string) {
-	bd.mux.Lock()
-	bd.setencias[nombre] = sentenciaSQL
-	bd.mux.Unlock()
-}
+/*
+Package bdsql gestiona de manera simple, rápida y eficiente; las sentencias
+que se realizan con el motor de base de datos Mysql/MariaDB.*/
+package bdsql
+
+import (
+	"database/sql"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Conectar crea una conección con el motor de base de datos Mysql/MariaDB.
+func Conectar(dsn string, maxConAbiertas, maxConOciosas int) (*BD, error) {
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, errorNuevo().asignarOrigen(err).asignarMotivoConexionAbrir()
+	}
+	if err = db.Ping(); err != nil {
+		return nil, errorNuevo().asignarOrigen(err).asignarMotivoConexionAbrir()
+	}
+	db.SetMaxOpenConns(maxConAbiertas)
+	db.SetMaxIdleConns(maxConOciosas)
+
+	var bd = NuevaBD(db)
+	if cfg, err := mysql.ParseDSN(dsn); err == nil && cfg.Loc != nil {
+		bd.ubicacion = cfg.Loc
+	}
+
+	return bd, nil
+}
+
+// NuevaBD crea la base de datos a partir de un manejador (database/sql) ya
+// abierto, por ejemplo uno creado con un controlador (driver) de pruebas.
+// La configuración del pool de conexiones queda a cargo de quien lo abrió.
+func NuevaBD(db *sql.DB) *BD {
+	var bd = &BD{db: db, ubicacion: time.UTC, salidaSimulacion: os.Stdout}
+	bd.setencias = make(map[string]string)
+
+	return bd
+}
+
+// BD representa el pool de conexiones con la base de datos.
+type BD struct {
+	db  *sql.DB    // manejador de la base de datos
+	mux sync.Mutex // bloqueador de exclusión mutua
+
+	dialecto Dialecto // variante del motor de base de datos
+
+	// sentencias almacena sentencias SQL para que no vuelvan a
+	// ser generadas por cada llamada
+	setencias map[string]string
+
+	// borradoLogico almacena las tablas que utilizan borrado lógico:
+	// la clave es el nombre de la tabla y el valor es el nombre del campo
+	// que registra el momento de la eliminación
+	borradoLogico map[string]string
+
+	// claveCursores es la clave con la cual se firman los cursores de
+	// paginación
+	claveCursores []byte
+
+	// ubicacion es la zona horaria de la conexión ('loc' del dsn), con la
+	// cual se representan los momentos al interpolar las sentencias
+	ubicacion *time.Location
+
+	// simulacion establece que las sentencias de escritura no se ejecuten:
+	// se escriben interpoladas en salidaSimulacion
+	simulacion       bool
+	salidaSimulacion io.Writer
+}
+
+// BorradoLogico registra que la tabla utiliza borrado lógico: en lugar de
+// eliminar los registros, se asigna el momento de la eliminación en el campo
+// recibido.
+// A partir del registro, la sentencia 'delete' de la tabla se convierte en
+// una sentencia 'update' y la sentencia 'select' excluye automáticamente los
+// registros eliminados.
+func (bd *BD) BorradoLogico(tabla, campo string) *BD {
+	bd.mux.Lock()
+	if bd.borradoLogico == nil {
+		bd.borradoLogico = make(map[string]string)
+	}
+	bd.borradoLogico[tabla] = campo
+	bd.mux.Unlock()
+
+	return bd
+}
+
+// Insertar representa la sentencia 'insert' de SQL.
+func (bd *BD) Insertar(nombre string) *insertar {
+	return nuevoInsertar(bd, bd.db, nombre)
+}
+
+// Modificar representa la sentencia 'update' de SQL.
+func (bd *BD) Modificar(nombre string) *modificar {
+	return nuevoModificar(bd, bd.db, nombre)
+}
+
+// Eliminar representa la sentencia 'delete' de SQL.
+func (bd *BD) Eliminar(nombre string) *eliminar {
+	return nuevoEliminar(bd, bd.db, nombre)
+}
+
+// Seleccionar representa la sentencia 'select' de SQL.
+func (bd *BD) Seleccionar(nombre string) *seleccionar {
+	return nuevoSeleccionar(bd, bd.db, nombre)
+}
+
+// Restaurar representa la sentencia 'update' de SQL que restaura los
+// registros eliminados de una tabla con borrado lógico.
+func (bd *BD) Restaurar(nombre string) *restaurar {
+	return nuevoRestaurar(bd, bd.db, nombre)
+}
+
+// CrearTabla representa la sentencia 'create table' de SQL.
+func (bd *BD) CrearTabla(tabla string) *crearTabla {
+	return &crearTabla{bd: bd, tabla: tabla}
+}
+
+// ModificarTabla representa la sentencia 'alter table' de SQL.
+func (bd *BD) ModificarTabla(tabla string) *modificarTabla {
+	return &modificarTabla{bd: bd, tabla: tabla}
+}
+
+// EliminarTabla representa la sentencia 'drop table' de SQL.
+func (bd *BD) EliminarTabla(tabla string) *eliminarTabla {
+	return &eliminarTabla{bd: bd, tabla: tabla}
+}
+
+// CrearIndice representa la sentencia 'create index' de SQL.
+func (bd *BD) CrearIndice(nombre string) *crearIndice {
+	return &crearIndice{bd: bd, nombre: nombre}
+}
+
+// TxIniciar inicia una nueva transacción.
+// Representa a la sentencia 'Begin' de SQL.
+func (bd *BD) TxIniciar() (*TX, error) {
+	txBD, err := bd.db.Begin()
+	if err != nil {
+		return nil, errorNuevo().asignarOrigen(err).asignarMotivoTxIniciar()
+	}
+
+	return &TX{bd: bd, tx: txBD}, nil
+}
+
+// Cerrar cierra la conexión con la base de datos.
+func (bd *BD) Cerrar() error {
+	if err := bd.db.Close(); err != nil {
+		return errorNuevo().asignarOrigen(err).asignarMotivoConexionCerrar()
+	}
+
+	return nil
+}
+
+// DB devuelve el manejador de la base de datos (database/sql) para las
+// operaciones que el paquete no contempla, como la ejecución de scripts.
+func (bd *BD) DB() *sql.DB {
+	return bd.db
+}
+
+// AsignarUbicacion establece la zona horaria con la que el controlador envía
+// los momentos (parámetro 'loc' del dsn), utilizada al interpolar las
+// sentencias. Conectar() la obtiene del dsn; por defecto se utiliza UTC.
+func (bd *BD) AsignarUbicacion(ubicacion *time.Location) *BD {
+	bd.mux.Lock()
+	bd.ubicacion = ubicacion
+	bd.mux.Unlock()
+
+	return bd
+}
+
+// -----------------------------------------------------------------------------
+
+// TX representa a una transacción de la base de datos.
+// Es quien tiene la resposabilidad de mantener y otorgar
+// las sentencias a ejecutar de SQL dentro de una transacción.
+type TX struct {
+	bd *BD
+	tx *sql.Tx
+}
+
+// Insertar representa la sentencia 'insert' de SQL.
+func (tx *TX) Insertar(nombre string) *insertar {
+	return nuevoInsertar(tx.bd, tx.tx, nombre)
+}
+
+// Modificar representa la sentencia 'update' de SQL.
+func (tx *TX) Modificar(nombre string) *modificar {
+	return nuevoModificar(tx.bd, tx.tx, nombre)
+}
+
+// Eliminar representa la sentencia 'delete' de SQL.
+func (tx *TX) Eliminar(nombre string) *eliminar {
+	return nuevoEliminar(tx.bd, tx.tx, nombre)
+}
+
+// Seleccionar representa la sentencia 'select' de SQL.
+func (tx *TX) Seleccionar(nombre string) *seleccionar {
+	return nuevoSeleccionar(tx.bd, tx.tx, nombre)
+}
+
+// Restaurar representa la sentencia 'update' de SQL que restaura los
+// registros eliminados de una tabla con borrado lógico.
+func (tx *TX) Restaurar(nombre string) *restaurar {
+	return nuevoRestaurar(tx.bd, tx.tx, nombre)
+}
+
+// SeleccionarSql(sentencia string, valores ...interface{}) *seleccionarSql
+
+// TxConfirmar representa a la sentencia 'commit' de SQL.
+func (tx *TX) TxConfirmar() error {
+	if err := tx.tx.Commit(); err != nil {
+		return errorNuevo().asignarOrigen(err).asignarMotivoTxConfirmar()
+	}
+
+	return nil
+}
+
+// TxRevertir representa a la sentencia 'rollback' de SQL.
+func (tx *TX) TxRevertir() error {
+	if err := tx.tx.Rollback(); err != nil {
+		return errorNuevo().asignarOrigen(err).asignarMotivoTxRevertir()
+	}
+
+	return nil
+}
+
+// TxFinalizar decide si confirma o revierte la transacción
+// tomando en cuenta el error recibido.
+// Si es nulo, confirma la transacción (realiza 'commit').
+// En caso de recibir un error, revierte la transacción (realiza 'rollback').
+func (tx *TX) TxFinalizar(err error) error {
+	if err != nil {
+		return tx.TxRevertir()
+	}
+
+	return tx.TxConfirmar()
+}
+
+// -----------------------------------------------------------------------------
+// funciones internas
+
+func (bd *BD) obtenerSentenciaSQL(nombre string) (string, bool) {
+	bd.mux.Lock()
+	s, ok := bd.setencias[nombre]
+	bd.mux.Unlock()
+
+	return s, ok
+}
+
+func (bd *BD) guardarSentenciaSQL(nombre, sentenciaSQL string) {
+	bd.mux.Lock()
+	bd.setencias[nombre] = sentenciaSQL
+	bd.mux.Unlock()
+}
+
+// campoBorradoLogico devuelve el campo de borrado lógico de la tabla y un
+// valor lógico que confirma si la tabla utiliza borrado lógico.
+func (bd *BD) campoBorradoLogico(tabla string) (string, bool) {
+	bd.mux.Lock()
+	campo, ok := bd.borradoLogico[tabla]
+	if !ok {
+		// tabla precedida por el nombre de la base de datos
+		if i := strings.LastIndex(tabla, "."); i >= 0 {
+			campo, ok = bd.borradoLogico[tabla[i+1:]]
+		}
+	}
+	bd.mux.Unlock()
+
+	return campo, ok
+}
+
+// tablaYAlias separa el nombre de la tabla de su alias.
+// Si la tabla no tiene alias, el alias es el propio nombre de la tabla.
+func tablaYAlias(tabla string) (string, string) {
+	var partes = strings.Fields(tabla)
+	switch {
+	case len(partes) == 0:
+		return "", ""
+	case len(partes) >= 3 && strings.EqualFold(partes[1], "as"):
+		return partes[0], partes[2]
+	case len(partes) >= 2:
+		return partes[0], partes[1]
+	default:
+		return partes[0], partes[0]
+	}
+}
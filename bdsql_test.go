@@ -1,155 +1,963 @@
-package bdsql
-
-import (
-	"fmt"
-	"testing"
-)
-
-const (
-	dsn                = "root:mysql@tcp(localhost:3306)/bdsql?charset=utf8&parseTime=true&clientFoundRows=true"
-	parametrosPostgres = "user=postgres password=postgres dbname=pruebas sslmode=disable"
-)
-
-func TestSeleccionar(t *testing.T) {
-	t.Skip()
-	bd, err := Conectar(dsn, 10, 0)
-	if err != nil {
-		t.Error("No es posible conectar con la bd:", err)
-	}
-
-	seleccionarEnCosas(bd)
-}
-func TestModificar(t *testing.T) {
-	t.Skip()
-	bd, err := Conectar(dsn, 10, 0)
-	if err != nil {
-		t.Error("No es posible conectar con la bd:", err)
-	}
-	if err := modificarEnCosas(bd, 11, "doce modificado otra vez", false, "obs modi"); err != nil {
-		fmt.Println("-1-----------------------------")
-		if errBdsql, ok := EsError(err); ok {
-			fmt.Println("Error: ", err)
-			fmt.Println("Origen:", errBdsql.ObtenerOrigen())
-		}
-		fmt.Println("-------------------------------")
-	} else {
-		fmt.Println("Modificado!")
-	}
-
-	if err := modificarEnCosas(bd, 12, "doce modificado otra vez", false, "obs modi"); err != nil {
-		fmt.Println("-2-----------------------------")
-		if errBdsql, ok := EsError(err); ok {
-			fmt.Println("Error: ", err)
-			fmt.Println("Origen:", errBdsql.ObtenerOrigen())
-		}
-		fmt.Println("-------------------------------")
-	} else {
-		fmt.Println("Modificado!")
-	}
-
-}
-func TestInsertarEnA(t *testing.T) {
-	t.Skip()
-	bd, err := Conectar(dsn, 10, 0)
-	if err != nil {
-		t.Error("No es posible conectar con la bd:", err)
-	}
-	id, err := insertarEnCosas(bd, "un nombre efg10", "{\"id\": 13, \"nombre\": \"un nombre\", \"unArray\": [{\"id\": 1}, {\"id\": 2}]", true, "observaciones locas")
-	if err != nil {
-		fmt.Println("-1-----------------------------")
-		fmt.Printf("No se insertó nada\n%v\n", err)
-		if errBdsql, ok := EsError(err); ok {
-			fmt.Println("Origen:", errBdsql.ObtenerOrigen())
-			fmt.Println("Es entrada duplicada:", errBdsql.EsEntradaDuplicada())
-		}
-		fmt.Println("-------------------------------")
-	} else {
-		fmt.Println("Primer id insertado:", id)
-	}
-
-	// id, err = insertarEnCosas(bd, "un nombre efg", "{\"id\": 13, \"nombre\": \"un nombre\", \"unArray\": [{\"id\": 1}, {\"id\": 2}]}", true, "observaciones locas")
-	// if err != nil {
-	// 	fmt.Println("-2-----------------------------")
-	// 	fmt.Printf("No se insertó nada\n%v\n", err)
-	// 	if errBdsql, ok := EsError(err); ok {
-	// 		fmt.Println("Origen:", errBdsql.ObtenerOrigen())
-	// 		fmt.Println("Es entrada duplicada:", errBdsql.EsEntradaDuplicada())
-	// 	}
-	// 	fmt.Println("-------------------------------")
-	// } else {
-	// 	fmt.Println("Segundo id insertado:", id)
-	// }
-}
-func TestInsertarEnSentenciaPreparada(t *testing.T) {
-	// t.Skip()
-	bd, err := Conectar(dsn, 10, 0)
-	if err != nil {
-		t.Error("No es posible conectar con la bd:", err)
-	}
-	sp, err := bd.
-		Insertar("").
-		Tabla("cosas").
-		Campos("nombre", "datos", "es_activo", "observaciones").
-		SentenciaPreparada()
-
-	defer sp.Cerrar()
-
-	var id int64
-	for i := 0; i < 3; i++ {
-		if err := sp.Valores(fmt.Sprintf("nombre-%v", i), "{}", true, fmt.Sprintf("observaciones-%v", i)).ObtenerID(&id).Ejecutar(); err != nil {
-			fmt.Println("Error:", err)
-			return
-		}
-		fmt.Println("Insertado:", id)
-	}
-}
-
-func insertarEnCosas(bd *BD, nombre, datos string, esActivo bool, observaciones string) (int64, error) {
-	var id int64
-	err := bd.
-		Insertar("cosasInsertar").
-		Tabla("cosas").
-		Campos("nombre", "datos", "es_activo", "observaciones").
-		Valores(nombre, datos, esActivo, observaciones).
-		ObtenerID(&id).
-		Ejecutar()
-
-	return id, err
-}
-
-func modificarEnCosas(bd *BD, id int64, nombre string, esActivo bool, observaciones string) error {
-	mod := bd.
-		Modificar("cosasModificar").
-		Tabla("cosas").
-		Campos("nombre", "es_activo", "observaciones").
-		Valores(nombre, esActivo, observaciones).
-		Condicion("id = ? ", id)
-
-	// s, err := mod.SQL()
-	// fmt.Println("SQL:", s, err)
-	return mod.Ejecutar()
-
-	// return err
-}
-
-func seleccionarEnCosas(bd *BD) error {
-	var cosas = []struct {
-		ID            int64  `bdsql:"id"`
-		Nombre        string `bdsql:"nombre"`
-		Datos         string
-		EsActivo      bool   `bdsql:"es_activo"`
-		Observaciones string `bdsql:"observaciones"`
-	}{}
-
-	var cant, err = bd.Seleccionar("seleccionarEnCosas").Tabla("cosas").Campos("*").Resultado(&cosas).Ejecutar()
-	if err != nil {
-		fmt.Println("ERROR:", err)
-		return err
-	}
-	fmt.Println("Cantidad:", cant)
-	for _, cosa := range cosas {
-		fmt.Println(cosa)
-	}
-
-	return nil
-}
+package bdsql
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	dsn                = "root:mysql@tcp(localhost:3306)/bdsql?charset=utf8&parseTime=true&clientFoundRows=true"
+	parametrosPostgres = "user=postgres password=postgres dbname=pruebas sslmode=disable"
+)
+
+func TestSeleccionar(t *testing.T) {
+	t.Skip()
+	bd, err := Conectar(dsn, 10, 0)
+	if err != nil {
+		t.Error("No es posible conectar con la bd:", err)
+	}
+
+	seleccionarEnCosas(bd)
+}
+func TestModificar(t *testing.T) {
+	t.Skip()
+	bd, err := Conectar(dsn, 10, 0)
+	if err != nil {
+		t.Error("No es posible conectar con la bd:", err)
+	}
+	if err := modificarEnCosas(bd, 11, "doce modificado otra vez", false, "obs modi"); err != nil {
+		fmt.Println("-1-----------------------------")
+		if errBdsql, ok := EsError(err); ok {
+			fmt.Println("Error: ", err)
+			fmt.Println("Origen:", errBdsql.ObtenerOrigen())
+		}
+		fmt.Println("-------------------------------")
+	} else {
+		fmt.Println("Modificado!")
+	}
+
+	if err := modificarEnCosas(bd, 12, "doce modificado otra vez", false, "obs modi"); err != nil {
+		fmt.Println("-2-----------------------------")
+		if errBdsql, ok := EsError(err); ok {
+			fmt.Println("Error: ", err)
+			fmt.Println("Origen:", errBdsql.ObtenerOrigen())
+		}
+		fmt.Println("-------------------------------")
+	} else {
+		fmt.Println("Modificado!")
+	}
+
+}
+func TestInsertarEnA(t *testing.T) {
+	t.Skip()
+	bd, err := Conectar(dsn, 10, 0)
+	if err != nil {
+		t.Error("No es posible conectar con la bd:", err)
+	}
+	id, err := insertarEnCosas(bd, "un nombre efg10", "{\"id\": 13, \"nombre\": \"un nombre\", \"unArray\": [{\"id\": 1}, {\"id\": 2}]", true, "observaciones locas")
+	if err != nil {
+		fmt.Println("-1-----------------------------")
+		fmt.Printf("No se insertó nada\n%v\n", err)
+		if errBdsql, ok := EsError(err); ok {
+			fmt.Println("Origen:", errBdsql.ObtenerOrigen())
+			fmt.Println("Es entrada duplicada:", errBdsql.EsEntradaDuplicada())
+		}
+		fmt.Println("-------------------------------")
+	} else {
+		fmt.Println("Primer id insertado:", id)
+	}
+
+	// id, err = insertarEnCosas(bd, "un nombre efg", "{\"id\": 13, \"nombre\": \"un nombre\", \"unArray\": [{\"id\": 1}, {\"id\": 2}]}", true, "observaciones locas")
+	// if err != nil {
+	// 	fmt.Println("-2-----------------------------")
+	// 	fmt.Printf("No se insertó nada\n%v\n", err)
+	// 	if errBdsql, ok := EsError(err); ok {
+	// 		fmt.Println("Origen:", errBdsql.ObtenerOrigen())
+	// 		fmt.Println("Es entrada duplicada:", errBdsql.EsEntradaDuplicada())
+	// 	}
+	// 	fmt.Println("-------------------------------")
+	// } else {
+	// 	fmt.Println("Segundo id insertado:", id)
+	// }
+}
+func TestModificarConVersionSQL(t *testing.T) {
+	bd := bdPrueba()
+	sentencia, err := bd.
+		Modificar("-").
+		Tabla("cosas").
+		Campos("nombre").
+		Condicion("id = ?", 1).
+		ConVersion("version", 3).
+		SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	esperada := "update cosas set nombre = ?, version = version + 1 where (id = ?) and version = ?;"
+	if sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, esperada)
+	}
+}
+
+func TestExpresionesSQL(t *testing.T) {
+	bd := bdPrueba()
+	sentencia, err := bd.
+		Modificar("-").
+		Tabla("productos").
+		Campos("nombre", "actualizado_en").
+		Valores("teclado", Expr("now()")).
+		Expresion("stock", "stock - ?", 3).
+		Condicion("id = ? and nombre <> '?'", 1).
+		SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	esperada := "update productos set nombre = ?, actualizado_en = now(), stock = stock - ? where id = ? and nombre <> '?';"
+	if sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, esperada)
+	}
+
+	_, valores, err := expandirSentencia("insert into t (a, b) values (?, ?);", []interface{}{Expr("coalesce(?, ?)", 1, 2), 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(valores) != "[1 2 3]" {
+		t.Errorf("valores incorrectos: %v", valores)
+	}
+}
+
+func TestBorradoLogicoSQL(t *testing.T) {
+	bd := bdPrueba().BorradoLogico("personas", "eliminado_en").BorradoLogico("personas_datos", "baja_en")
+
+	casos := []struct {
+		obtener  func() (string, error)
+		esperada string
+	}{
+		{
+			bd.Eliminar("-").Tabla("personas").Condicion("id = ?", 1).SQL,
+			"update personas set eliminado_en = now() where (id = ?) and eliminado_en is null;",
+		},
+		{
+			bd.Restaurar("-").Tabla("personas").Condicion("id = ?", 1).SQL,
+			"update personas set eliminado_en = null where (id = ?) and eliminado_en is not null;",
+		},
+		{
+			bd.Seleccionar("-").Tabla("personas p").Campos("*").Condicion("p.activo = ?", true).SQL,
+			"select * from personas p where (p.activo = ?) and p.eliminado_en is null;",
+		},
+		{
+			bd.Seleccionar("-").Tabla("telefonos t").Campos("*").JuntarCon("personas p", "p.id = t.persona_id").SQL,
+			"select * from telefonos t inner join personas p on (p.id = t.persona_id) and p.eliminado_en is null;",
+		},
+		{
+			bd.Seleccionar("-").Tabla("telefonos t").Campos("*").JuntarUsando("personas p", "documento").SQL,
+			"select * from telefonos t inner join personas p using (documento) where p.eliminado_en is null;",
+		},
+		{
+			bd.Seleccionar("-").Tabla("telefonos t").Campos("*").JuntarNatural("personas p").SQL,
+			"select * from telefonos t natural join personas p where p.eliminado_en is null;",
+		},
+		{
+			bd.Seleccionar("-").Tabla("telefonos t").Campos("*").JuntarCruzado("personas p").Condicion("t.tipo = ? or t.tipo = ?", "movil", "fijo").SQL,
+			"select * from telefonos t cross join personas p where (t.tipo = ? or t.tipo = ?) and p.eliminado_en is null;",
+		},
+		{
+			bd.Seleccionar("-").Tabla("telefonos t").Campos("*").JuntarDerecha("personas p", "p.id = t.persona_id").SQL,
+			"select * from telefonos t right join personas p on p.id = t.persona_id where p.eliminado_en is null;",
+		},
+		{
+			bd.Seleccionar("-").Tabla("telefonos t").Campos("*").JuntarExterior("personas p", "p.id = t.persona_id").SQL,
+			"select * from telefonos t left join personas p on (p.id = t.persona_id) and p.eliminado_en is null union " +
+				"select * from telefonos t right join personas p on p.id = t.persona_id where p.eliminado_en is null;",
+		},
+		{
+			// la tabla principal solo con sus registros eliminados; la
+			// tabla juntada sin sus registros eliminados
+			bd.Seleccionar("-").Tabla("personas p").Campos("*").JuntarNatural("personas_datos d").SoloEliminados().SQL,
+			"select * from personas p natural join personas_datos d where p.eliminado_en is not null and d.baja_en is null;",
+		},
+		{
+			bd.Seleccionar("-").Tabla("telefonos t").Campos("*").JuntarDerecha("personas p", "p.id = t.persona_id").IncluirEliminados().SQL,
+			"select * from telefonos t right join personas p on p.id = t.persona_id;",
+		},
+		{
+			bd.Seleccionar("-").Tabla("personas").Campos("*").SoloEliminados().SQL,
+			"select * from personas where personas.eliminado_en is not null;",
+		},
+		{
+			bd.Seleccionar("-").Tabla("personas").Campos("*").IncluirEliminados().SQL,
+			"select * from personas;",
+		},
+	}
+	for _, c := range casos {
+		sentencia, err := c.obtener()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sentencia != c.esperada {
+			t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, c.esperada)
+		}
+	}
+}
+
+func TestListasEnCondicionSQL(t *testing.T) {
+	bd := bdPrueba()
+
+	// la sentencia almacenada por su nombre no depende de la cantidad de
+	// elementos de la lista
+	casos := []struct {
+		ids      []int64
+		esperada string
+	}{
+		{[]int64{1, 2, 3}, "delete from personas where id in (?, ?, ?);"},
+		{[]int64{4}, "delete from personas where id in (?);"},
+		{[]int64{}, "delete from personas where id in (select null from dual where false);"},
+	}
+	for _, c := range casos {
+		sentencia, err := bd.Eliminar("personasEliminarVarias").Tabla("personas").Condicion("id in (?)", c.ids).SQL()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sentencia != c.esperada {
+			t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, c.esperada)
+		}
+	}
+
+	sentencia, valores, err := expandirSentencia(
+		"select * from t where a in (?) and b = ? and c = '?' having d in (?);",
+		[]interface{}{[]string{"x", "y"}, []byte("z"), []int{7, 8}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if esperada := "select * from t where a in (?, ?) and b = ? and c = '?' having d in (?, ?);"; sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, esperada)
+	}
+	if len(valores) != 5 {
+		t.Errorf("cantidad de valores incorrecta: %v", valores)
+	}
+}
+
+func TestParametrosNombrados(t *testing.T) {
+	filtro := struct {
+		Estado string `bdsql:"estado"`
+		Zona   string `bdsql:"zona"`
+		Oculto string `bdsql:"-"`
+	}{"activo", "centro", "x"}
+
+	casos := []struct {
+		condicion string
+		valores   []interface{}
+		esperada  string
+		valoresOk string
+	}{
+		{
+			"estado = :estado and (zona = :zona or zona_alt = :zona) and texto = ':zona' and @@autocommit = 1",
+			[]interface{}{map[string]interface{}{"estado": "activo", "zona": "centro"}},
+			"estado = ? and (zona = ? or zona_alt = ?) and texto = ':zona' and @@autocommit = 1",
+			"[activo centro centro]",
+		},
+		{
+			"estado = :estado and zona = :zona",
+			[]interface{}{&filtro},
+			"estado = ? and zona = ?",
+			"[activo centro]",
+		},
+		{
+			// '@nombre' es una variable de sesión de mysql: la condición
+			// posicional no se modifica y la estructura es el valor del
+			// parámetro
+			"estado = @estado and zona = ?",
+			[]interface{}{filtro},
+			"estado = @estado and zona = ?",
+			"[{activo centro x}]",
+		},
+		{
+			"id > @ultimo_id and tipo = ?",
+			[]interface{}{3},
+			"id > @ultimo_id and tipo = ?",
+			"[3]",
+		},
+		{
+			"id in (:ids) and tipo = :tipo",
+			[]interface{}{sql.Named("ids", []int{1, 2}), sql.Named("tipo", 3)},
+			"id in (?) and tipo = ?",
+			"[[1 2] 3]",
+		},
+		{
+			"id = ? and x::int = 1",
+			[]interface{}{7},
+			"id = ? and x::int = 1",
+			"[7]",
+		},
+	}
+	for _, c := range casos {
+		condicion, valores, err := resolverNombrados(c.condicion, c.valores)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if condicion != c.esperada || fmt.Sprint(valores) != c.valoresOk {
+			t.Errorf("condición incorrecta:\n obtenida: %v %v\n esperada: %v %v", condicion, valores, c.esperada, c.valoresOk)
+		}
+	}
+
+	_, _, err := resolverNombrados("id = :id and tipo = :tipo", []interface{}{map[string]interface{}{"id": 1}})
+	if e, ok := EsError(err); !ok || !e.EsParametrosNombradosFaltantes() {
+		t.Errorf("se esperaba el error de parámetros faltantes: %v", err)
+	}
+	_, _, err = resolverNombrados("id = :id", []interface{}{map[string]interface{}{"id": 1, "tipo": 2}})
+	if e, ok := EsError(err); !ok || !e.EsParametrosNombradosSinUtilizar() {
+		t.Errorf("se esperaba el error de parámetros sin utilizar: %v", err)
+	}
+
+	_, err = bdPrueba().Eliminar("-").Tabla("t").Condicion("id = :id", map[string]interface{}{}).SQL()
+	if err == nil {
+		t.Error("se esperaba un error al generar la sentencia")
+	}
+}
+
+func TestPredicadosSQL(t *testing.T) {
+	bd := bdPrueba()
+
+	var estado string
+	sentencia, err := bd.
+		Seleccionar("-").
+		Tabla("personas p").
+		Campos("*").
+		JuntarCon("telefonos t", Y(Crudo("t.persona_id = p.id"), Igual("t.tipo", "movil"))).
+		Condicion(Y(
+			Igual("p.estado", estado).Opcional(),
+			O(Mayor("p.edad", 18), EsNulo("p.edad")),
+			Entre("p.alta", nil, "2020-12-31").Opcional(),
+			En("p.zona", []string{"centro", "norte"}),
+			No(Como("p.apellidos", "%z%")),
+		)).
+		SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	esperada := "select * from personas p inner join telefonos t on ((t.persona_id = p.id) and t.tipo = ?) " +
+		"where ((p.edad > ? or p.edad is null) and p.alta <= ? and p.zona in (?, ?) and not (p.apellidos like ?));"
+	if sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, esperada)
+	}
+
+	sentencia, err = bd.Seleccionar("-").Tabla("personas").Campos("*").Condicion(Igual("estado", "").Opcional()).SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if esperada := "select * from personas where 1 = 1;"; sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, esperada)
+	}
+
+	// no se permite modificar ni eliminar con una condición omitida
+	_, err = bd.Eliminar("-").Tabla("personas").Condicion(Igual("id", 0).Opcional()).SQL()
+	if e, ok := EsError(err); !ok || !e.EsCondicionVacia() {
+		t.Errorf("se esperaba el error de condición vacía: %v", err)
+	}
+}
+
+func TestSubconsultasSQL(t *testing.T) {
+	bd := bdPrueba()
+
+	deudores := bd.Seleccionar("-").Tabla("deudas").Campos("persona_id").Condicion("saldo > ?", 0)
+	totales := bd.Seleccionar("-").Tabla("compras").Campos("persona_id", "sum(importe) as total").AgruparPor("persona_id")
+	cantidad := bd.Seleccionar("-").Tabla("telefonos t").Campos("count(*)").Condicion("t.persona_id = p.id")
+
+	sel := bd.
+		Seleccionar("-").
+		Tabla("personas p").
+		Campos("p.id", "c.total").
+		CampoSubconsulta(cantidad, "telefonos").
+		JuntarIzquierdaSubconsulta(totales, "c", "c.persona_id = p.id and c.total > ?", 100).
+		Condicion(Y(
+			Crudo("p.id in (?)", deudores),
+			NoExiste(bd.Seleccionar("-").Tabla("bajas b").Campos("1").Condicion("b.persona_id = p.id and b.motivo = ?", "fraude")),
+		))
+	sentencia, err := sel.SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	esperada := "select p.id, c.total, (select count(*) from telefonos t where t.persona_id = p.id) as telefonos from personas p " +
+		"left join (select persona_id, sum(importe) as total from compras group by persona_id) as c on c.persona_id = p.id and c.total > ? " +
+		"where ((p.id in (select persona_id from deudas where saldo > ?)) and not exists (select 1 from bajas b where b.persona_id = p.id and b.motivo = ?));"
+	if sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, esperada)
+	}
+
+	sentencia, err = bd.Seleccionar("-").TablaSubconsulta(totales, "c").Campos("max(c.total)").SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if esperada := "select max(c.total) from (select persona_id, sum(importe) as total from compras group by persona_id) as c;"; sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, esperada)
+	}
+}
+
+func TestUnionesYConSQL(t *testing.T) {
+	bd := bdPrueba()
+
+	sentencia, err := bd.
+		Seleccionar("-").
+		Tabla("pedidos").
+		Campos("id", "fecha").
+		Condicion("cliente_id = ?", 1).
+		UnirTodos(bd.Seleccionar("-").Tabla("pedidos_historicos").Campos("id", "fecha").Condicion("cliente_id = ?", 1)).
+		Unir(bd.Seleccionar("-").Tabla("pedidos_borrador").Campos("id", "fecha").OrdenarPor("fecha desc").Limitar(5)).
+		OrdenarPor("fecha desc").
+		Limitar(10).
+		SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	esperada := "select id, fecha from pedidos where cliente_id = ? " +
+		"union all select id, fecha from pedidos_historicos where cliente_id = ? " +
+		"union (select id, fecha from pedidos_borrador order by fecha desc limit 5) " +
+		"order by fecha desc limit 10;"
+	if sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, esperada)
+	}
+
+	arbol := bd.
+		Seleccionar("-").
+		Tabla("categorias").
+		Campos("id", "padre_id", "1").
+		Condicion("id = ?", 3).
+		UnirTodos(bd.Seleccionar("-").Tabla("categorias c").Campos("c.id", "c.padre_id", "a.nivel + 1").JuntarCon("arbol a", "c.padre_id = a.id"))
+	sentencia, err = bd.
+		Seleccionar("-").
+		ConRecursivo("arbol(id, padre_id, nivel)", arbol).
+		Tabla("arbol").
+		Campos("*").
+		OrdenarPor("nivel").
+		SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	esperada = "with recursive arbol(id, padre_id, nivel) as (select id, padre_id, 1 from categorias where id = ? " +
+		"union all select c.id, c.padre_id, a.nivel + 1 from categorias c inner join arbol a on c.padre_id = a.id) " +
+		"select * from arbol order by nivel;"
+	if sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, esperada)
+	}
+}
+
+func TestModificadoresSeleccionarSQL(t *testing.T) {
+	bd := bdPrueba()
+
+	sel := bd.
+		Seleccionar("-").
+		Sugerencia("MAX_EXECUTION_TIME(1000)").
+		Distinto().
+		Tabla("empleados e").
+		Campos("e.departamento_id", "row_number() over w as orden").
+		JuntarCon("departamentos d", "d.id = e.departamento_id").
+		ForzarIndice("e", "idx_alta").
+		UsarIndice("departamentos", "primary").
+		Ventana("w", "e.departamento_id", "e.salario desc")
+	sentencia, err := sel.SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	esperada := "select /*+ MAX_EXECUTION_TIME(1000) */ distinct e.departamento_id, row_number() over w as orden " +
+		"from empleados e force index (idx_alta) inner join departamentos d use index (primary) on d.id = e.departamento_id " +
+		"window w as (partition by e.departamento_id order by e.salario desc);"
+	if sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, esperada)
+	}
+
+	// Mariadb no admite las sugerencias al optimizador
+	bd.AsignarDialecto(Mariadb)
+	_, err = sel.SQL()
+	if e, ok := EsError(err); !ok || !e.EsNoAdmitidoPorDialecto() {
+		t.Errorf("se esperaba el error de característica no admitida por el dialecto: %v", err)
+	}
+}
+
+func TestJuntasSQL(t *testing.T) {
+	bd := bdPrueba()
+
+	// las juntas conservan el orden en que se establecen
+	sentencia, err := bd.
+		Seleccionar("-").
+		Tabla("pedidos p").
+		Campos("*").
+		JuntarIzquierda("clientes c", "c.id = p.cliente_id and c.estado = ?", "activo").
+		JuntarCon("sucursales s", "s.id = c.sucursal_id").
+		JuntarUsando("pedidos_detalle d", "pedido_id").
+		JuntarCruzado("monedas m").
+		JuntarNatural("paises").
+		SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	esperada := "select * from pedidos p left join clientes c on c.id = p.cliente_id and c.estado = ? " +
+		"inner join sucursales s on s.id = c.sucursal_id inner join pedidos_detalle d using (pedido_id) " +
+		"cross join monedas m natural join paises;"
+	if sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, esperada)
+	}
+
+	// junta externa completa emulada
+	sel := bd.
+		Seleccionar("-").
+		Tabla("empleados e").
+		Campos("e.nombre", "d.nombre").
+		JuntarExterior("departamentos d", "d.id = e.departamento_id and d.activo = ?", true).
+		Condicion("e.alta > ?", "2020-01-01").
+		OrdenarPor("1")
+	sentencia, err = sel.SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	esperada = "select e.nombre, d.nombre from empleados e left join departamentos d on d.id = e.departamento_id and d.activo = ? where e.alta > ? " +
+		"union select e.nombre, d.nombre from empleados e right join departamentos d on d.id = e.departamento_id and d.activo = ? where e.alta > ? " +
+		"order by 1;"
+	if sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, esperada)
+	}
+	if parametros := sel.parametros(); len(parametros) != 4 || parametros[2] != true || parametros[3] != "2020-01-01" {
+		t.Errorf("parámetros incorrectos: %v", parametros)
+	}
+}
+
+func TestPaginacionSQL(t *testing.T) {
+	bd := bdPrueba().AsignarClaveDeCursores([]byte("clave de prueba"))
+
+	// salto sin límite
+	sentencia, err := bd.Seleccionar("-").Tabla("auditoria").Campos("*").Saltar(20).SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if esperada := "select * from auditoria limit 18446744073709551615 offset 20;"; sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, esperada)
+	}
+
+	// cantidad total de registros
+	sel := bd.
+		Seleccionar("-").
+		Tabla("auditoria a").
+		Campos("a.id", "a.fecha").
+		JuntarCon("usuarios u", "u.id = a.usuario_id").
+		Condicion("u.activo = ?", true).
+		OrdenarPor("a.fecha desc", "a.id desc").
+		Limitar(2)
+	sentencia, valores, err := sel.generarSQLContar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if esperada := "select count(*) from auditoria a inner join usuarios u on u.id = a.usuario_id where u.activo = ?;"; sentencia != esperada || len(valores) != 1 {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v %v\n esperada: %v", sentencia, valores, esperada)
+	}
+
+	// cursor de la página siguiente
+	fecha := time.Date(2020, 12, 1, 10, 30, 0, 0, time.UTC)
+	registros := []struct {
+		ID    int64     `bdsql:"id"`
+		Fecha time.Time `bdsql:"fecha"`
+	}{{ID: 9, Fecha: fecha.Add(time.Hour)}, {ID: 7, Fecha: fecha}}
+	sel.Resultado(&registros)
+	cursor, err := sel.SiguienteCursor()
+	if err != nil || cursor == "" {
+		t.Fatalf("no se obtuvo el cursor: %v", err)
+	}
+
+	sentencia, err = sel.DespuesDe(cursor).SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	esperada := "select a.id, a.fecha from auditoria a inner join usuarios u on u.id = a.usuario_id " +
+		"where (u.activo = ?) and (a.fecha < ? or (a.fecha = ? and a.id < ?)) order by a.fecha desc, a.id desc limit 2;"
+	if sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, esperada)
+	}
+	if v := sel.parametros(); len(v) != 4 || v[1] != fecha || v[3] != int64(7) {
+		t.Errorf("parámetros incorrectos: %v", v)
+	}
+
+	// cursor alterado
+	_, err = sel.DespuesDe("W10" + cursor[3:]).SQL()
+	if e, ok := EsError(err); !ok || !e.EsCursorInvalido() {
+		t.Errorf("se esperaba el error de cursor inválido: %v", err)
+	}
+
+	// tamaño de página incorrecto
+	for _, tamaño := range []int{0, -5} {
+		_, _, err = bd.Seleccionar("-").Tabla("auditoria").Campos("*").Resultado(&registros).Paginar(2, tamaño)
+		if e, ok := EsError(err); !ok || !e.EsPaginaTamañoIncorrecto() {
+			t.Errorf("se esperaba el error de tamaño de página incorrecto para %v: %v", tamaño, err)
+		}
+	}
+}
+
+func TestContarYExisteSQL(t *testing.T) {
+	bd := bdPrueba()
+
+	sel := bd.
+		Seleccionar("-").
+		Tabla("pedidos p").
+		Campos("p.cliente_id", "sum(p.importe) as total").
+		JuntarCon("clientes c", "c.id = p.cliente_id").
+		Condicion("c.zona = ?", "centro").
+		AgruparPor("p.cliente_id").
+		Teniendo("sum(p.importe) > ?", 1000).
+		OrdenarPor("total desc").
+		Limitar(10)
+
+	sentencia, valores, err := sel.generarSQLContar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	esperada := "select count(*) from (select p.cliente_id, sum(p.importe) as total from pedidos p inner join clientes c on c.id = p.cliente_id " +
+		"where c.zona = ? group by p.cliente_id having sum(p.importe) > ?) as t;"
+	if sentencia != esperada || len(valores) != 2 {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v %v\n esperada: %v", sentencia, valores, esperada)
+	}
+
+	sentencia, _, err = bd.Seleccionar("-").Tabla("pedidos").Campos("*").Condicion("cliente_id = ?", 1).OrdenarPor("id").generarSQLExiste()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if esperada := "select exists (select 1 from pedidos where cliente_id = ?);"; sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, esperada)
+	}
+}
+
+func TestAgregadosSQL(t *testing.T) {
+	bd := bdPrueba()
+
+	sel := bd.Seleccionar("-").Tabla("ventas").Campos("*").Condicion("zona = ?", "centro").OrdenarPor("fecha").Limitar(10)
+	sentencia, _, err := sel.generarSQLAgregado("sum", "importe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if esperada := "select sum(importe) from ventas where zona = ?;"; sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, esperada)
+	}
+
+	sel = bd.Seleccionar("-").Tabla("ventas").Campos("zona", "sum(importe) as total").AgruparPor("zona")
+	sentencia, _, err = sel.generarSQLAgregado("max", "total")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if esperada := "select max(t.total) from (select zona, sum(importe) as total from ventas group by zona) as t;"; sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, esperada)
+	}
+
+	// el destino de Maximo y Minimo debe ser un puntero
+	var maximo float64
+	if _, err := sel.Maximo("total", maximo); err == nil {
+		t.Error("se esperaba un error por destino sin puntero")
+	}
+}
+
+func TestEsquemaDDLSQL(t *testing.T) {
+	bd := bdPrueba()
+
+	ct := bd.CrearTabla("telefonos").SiNoExiste().Motor("InnoDB").JuegoDeCaracteres("utf8mb4").Comentario("teléfonos de l'agenda")
+	ct.Columna("id").EnteroGrande().SinSigno().AutoIncremental().ClavePrimaria()
+	ct.Columna("persona_id").EnteroGrande().SinSigno().NoNulo()
+	ct.Columna("numero").Texto(30).NoNulo().PorDefecto("''")
+	ct.IndiceUnico("uq_numero", "numero")
+	ct.ClaveForanea("persona_id").Referencia("personas", "id").AlEliminar("cascade")
+	sentencia, err := ct.SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	esperada := "create table if not exists telefonos (" +
+		"id bigint unsigned not null auto_increment primary key, " +
+		"persona_id bigint unsigned not null, " +
+		"numero varchar(30) not null default '', " +
+		"unique index uq_numero (numero), " +
+		"constraint fk_telefonos_persona_id foreign key (persona_id) references personas (id) on delete cascade" +
+		") engine = InnoDB default charset = utf8mb4 comment = 'teléfonos de l''agenda';"
+	if sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, esperada)
+	}
+
+	mt := bd.ModificarTabla("telefonos")
+	mt.AgregarColumna("tipo").Texto(10).Despues("numero")
+	mt.RenombrarColumna("numero", "telefono").EliminarIndice("uq_numero")
+	sentencia, err = mt.SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if esperada := "alter table telefonos add column tipo varchar(10) after numero, rename column numero to telefono, drop index uq_numero;"; sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v\n esperada: %v", sentencia, esperada)
+	}
+
+	// columna sin tipo
+	ct = bd.CrearTabla("telefonos")
+	ct.Columna("id")
+	if _, err := ct.SQL(); err == nil || !err.(*errorPaquete).EsColumnaSinTipo() {
+		t.Errorf("se esperaba el error de columna sin tipo: %v", err)
+	}
+
+	// 'if not exists' en los índices solo es admitido por Mariadb
+	ci := bd.CrearIndice("ix_tipo").Tabla("telefonos").Columnas("tipo").SiNoExiste()
+	if _, err := ci.SQL(); err == nil || !err.(*errorPaquete).EsNoAdmitidoPorDialecto() {
+		t.Errorf("se esperaba el error de dialecto: %v", err)
+	}
+	bd.AsignarDialecto(Mariadb)
+	if sentencia, err := ci.SQL(); err != nil || sentencia != "create index if not exists ix_tipo on telefonos (tipo);" {
+		t.Errorf("sentencia incorrecta: %v %v", sentencia, err)
+	}
+}
+
+func TestDiferenciasDeEstructura(t *testing.T) {
+	tabla := &Tabla{Nombre: "personas", Columnas: []Columna{
+		{Nombre: "id", TipoDeDato: "bigint"},
+		{Nombre: "apellidos", TipoDeDato: "varchar"},
+		{Nombre: "alta", TipoDeDato: "varchar"},
+	}}
+	type persona struct {
+		ID        int64
+		Apellidos string    `bdsql:"apellidos"`
+		Alta      time.Time `bdsql:"alta"`
+		Telefono  string
+		Temporal  string `bdsql:"-"`
+	}
+
+	diferencias := diferenciasDeEstructura(tabla, reflect.TypeOf(persona{}))
+	esperadas := []string{
+		"la columna 'alta' () no es compatible con el campo Alta (time.Time)",
+		"no existe la columna 'telefono' (campo Telefono)",
+	}
+	if !reflect.DeepEqual(diferencias, esperadas) {
+		t.Errorf("diferencias incorrectas:\n obtenidas: %q\n esperadas: %q", diferencias, esperadas)
+	}
+
+	// los tipos nulos de database/sql se verifican por el tipo de su valor
+	if !tipoCompatible(reflect.TypeOf(sql.NullInt64{}), "bigint") || tipoCompatible(reflect.TypeOf(sql.NullTime{}), "varchar") {
+		t.Error("compatibilidad incorrecta de los tipos nulos")
+	}
+
+	// valores por defecto de Mariadb
+	if v := Mariadb.normalizarPorDefecto(sql.NullString{String: "'l''agenda'", Valid: true}); v.String != "l'agenda" {
+		t.Errorf("valor por defecto incorrecto: %v", v.String)
+	}
+	if v := Mariadb.normalizarPorDefecto(sql.NullString{String: "NULL", Valid: true}); v.Valid {
+		t.Errorf("se esperaba un valor por defecto nulo: %v", v.String)
+	}
+}
+
+func TestRepositorio(t *testing.T) {
+	type persona struct {
+		Documento string `bdsql:"documento,clave"`
+		Tipo      string `bdsql:"tipo, clave"`
+		Apellidos string
+		Version   int `bdsql:"version,version"`
+		Temporal  int `bdsql:"-"`
+	}
+	repo := NuevoRepositorio[persona](bdPrueba(), "personas")
+	if repo.err != nil {
+		t.Fatal(repo.err)
+	}
+	if fmt.Sprint(repo.columnas) != "[documento tipo apellidos version]" || len(repo.claves) != 2 || repo.version.columna != "version" {
+		t.Errorf("repositorio incorrecto: %+v", repo)
+	}
+	if condicion, err := repo.condicionClave([]interface{}{"20123", "dni"}); err != nil || condicion != "documento = ? and tipo = ?" {
+		t.Errorf("condición incorrecta: %v %v", condicion, err)
+	}
+	if _, _, err := repo.ObtenerPorID("20123"); err == nil || !err.(*errorPaquete).EsCamposValoresDiferenteCantidad() {
+		t.Errorf("se esperaba el error de cantidad de valores: %v", err)
+	}
+	if err := repo.Insertar(nil); err == nil || !err.(*errorPaquete).EsRepositorioIncorrecto() {
+		t.Errorf("se esperaba el error de repositorio incorrecto: %v", err)
+	}
+	if err := repo.Modificar(nil); err == nil || !err.(*errorPaquete).EsRepositorioIncorrecto() {
+		t.Errorf("se esperaba el error de repositorio incorrecto: %v", err)
+	}
+
+	// T no es una estructura: el error se devuelve antes de analizar el
+	// registro
+	var numero int
+	if err := NuevoRepositorio[int](bdPrueba(), "numeros").Modificar(&numero); err == nil || !err.(*errorPaquete).EsRepositorioIncorrecto() {
+		t.Errorf("se esperaba el error de repositorio incorrecto: %v", err)
+	}
+
+	// sin clave primaria
+	type registro struct {
+		Mensaje string
+	}
+	if err := NuevoRepositorio[registro](bdPrueba(), "registros").Eliminar(1); err == nil || !err.(*errorPaquete).EsRepositorioIncorrecto() {
+		t.Errorf("se esperaba el error de repositorio incorrecto: %v", err)
+	}
+
+	// los tipos locales con el mismo nombre y los tipos anónimos no
+	// comparten los nombres de las sentencias si sus columnas difieren
+	otro := func() string {
+		type persona struct {
+			Documento string `bdsql:"documento,clave"`
+			Nombres   string
+		}
+		return NuevoRepositorio[persona](bdPrueba(), "personas").nombre
+	}()
+	anonimo := NuevoRepositorio[struct {
+		Documento string `bdsql:"documento,clave"`
+	}](bdPrueba(), "personas").nombre
+	if repo.nombre == otro || repo.nombre == anonimo || otro == anonimo {
+		t.Errorf("nombres de sentencias repetidos:\n %v\n %v\n %v", repo.nombre, otro, anonimo)
+	}
+
+	// las opciones de la etiqueta no forman parte del nombre del campo
+	if relacion := relacionDeCampos(reflect.TypeOf(persona{})); relacion["documento"] != "Documento" || relacion["version"] != "Version" {
+		t.Errorf("relación de campos incorrecta: %v", relacion)
+	}
+}
+
+func TestModoSimulacion(t *testing.T) {
+	var salida strings.Builder
+	bd := bdPrueba().ModoSimulacion(true).SalidaSimulacion(&salida)
+	bd.ubicacion = time.FixedZone("ART", -3*60*60)
+
+	var id int64
+	err := bd.Insertar("-").
+		Tabla("cosas").
+		Campos("nombre", "alta", "baja", "es_activo").
+		Valores("O'Brien", time.Date(2021, 3, 4, 15, 0, 0, 0, time.UTC), nil, true).
+		ObtenerID(&id).
+		Ejecutar()
+	if err != nil || id != 0 {
+		t.Fatal(id, err)
+	}
+	err = bd.Modificar("-").
+		Tabla("cosas").
+		Expresion("stock", "stock - ?", 2).
+		Condicion("id in (?)", []int{1, 2}).
+		ConVersion("version", 3).
+		Ejecutar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// los valores se validan como al ejecutarse
+	if err = bd.Eliminar("-").Tabla("cosas").Condicion("id = ?").Ejecutar(); err == nil {
+		t.Error("se esperaba el error de valores de la condición vacíos")
+	}
+
+	esperada := "insert into cosas (nombre, alta, baja, es_activo) values ('O''Brien', '2021-03-04 12:00:00', null, true);\n" +
+		"update cosas set stock = stock - 2, version = version + 1 where (id in (1, 2)) and version = 3;\n"
+	if salida.String() != esperada {
+		t.Errorf("salida incorrecta:\n obtenida: %v\n esperada: %v", salida.String(), esperada)
+	}
+}
+
+func TestSQLInterpolado(t *testing.T) {
+	bd := bdPrueba().AsignarUbicacion(time.FixedZone("ART", -3*60*60))
+	momento := time.Date(2021, 3, 4, 15, 30, 0, 250000000, time.UTC)
+
+	casos := []struct {
+		valor    interface{}
+		esperado string
+	}{
+		{"O'Brien", `'O''Brien'`},
+		{`C:\temp\`, `'C:\\temp\\'`},
+		{"\\'; drop table cosas; -- ", `'\\''; drop table cosas; -- '`},
+		{"línea 1\nlínea 2\r\x00\x1a", `'línea 1\nlínea 2\r\0\Z'`},
+		{"¿qué? ?", `'¿qué? ?'`},
+		{[]byte{0x00, 0x27, 0xff}, `x'0027ff'`},
+		{[]byte{}, `x''`},
+		{momento, `'2021-03-04 12:30:00.25'`},
+		{time.Time{}, `'0000-00-00'`},
+		{nil, `null`},
+		{sql.NullString{}, `null`},
+		{sql.NullInt64{Int64: 7, Valid: true}, `7`},
+		{true, `true`},
+		{uint64(18446744073709551615), `18446744073709551615`},
+		{-1.5, `-1.5`},
+	}
+	for _, c := range casos {
+		sentencia, err := bd.Seleccionar("-").Tabla("cosas").Campos("id").Condicion("valor = ? and id > ?", c.valor, 0).SQLInterpolado()
+		if esperada := "select id from cosas where valor = " + c.esperado + " and id > 0;"; err != nil || sentencia != esperada {
+			t.Errorf("sentencia incorrecta para %#v:\n obtenida: %v %v\n esperada: %v", c.valor, sentencia, err, esperada)
+		}
+	}
+
+	// listas, expresiones y parámetros dentro de cadenas de texto
+	sentencia, err := bd.Modificar("-").
+		Tabla("cosas").
+		Campos("nombre").
+		Valores("a?b").
+		Expresion("nota", "concat(nota, '?', ?)", "x").
+		Condicion("id in (?) and etiqueta <> '?'", []int{1, 2}).
+		SQLInterpolado()
+	if esperada := "update cosas set nombre = 'a?b', nota = concat(nota, '?', 'x') where id in (1, 2) and etiqueta <> '?';"; err != nil || sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v %v\n esperada: %v", sentencia, err, esperada)
+	}
+
+	// sentencia preparada
+	sp := &sentenciaPreparadaInsertar{bd: bd, sentencia: "insert into cosas (nombre, alta) values (?, ?);"}
+	sentencia, err = sp.Valores("Ana", momento).SQLInterpolado()
+	if esperada := "insert into cosas (nombre, alta) values ('Ana', '2021-03-04 12:30:00.25');"; err != nil || sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v %v\n esperada: %v", sentencia, err, esperada)
+	}
+
+	// valores que no pueden representarse como literales
+	for _, valor := range []interface{}{math.NaN(), complex(1, 2)} {
+		_, err = bd.Eliminar("-").Tabla("cosas").Condicion("id = ?", valor).SQLInterpolado()
+		if e, ok := EsError(err); !ok || !e.EsValorNoInterpolable() {
+			t.Errorf("se esperaba el error de valor no interpolable para %#v: %v", valor, err)
+		}
+	}
+}
+
+// bdPrueba devuelve una base de datos sin conexión, útil para verificar las
+// sentencias SQL generadas.
+func bdPrueba() *BD {
+	return &BD{setencias: make(map[string]string)}
+}
+
+func insertarEnCosas(bd *BD, nombre, datos string, esActivo bool, observaciones string) (int64, error) {
+	var id int64
+	err := bd.
+		Insertar("cosasInsertar").
+		Tabla("cosas").
+		Campos("nombre", "datos", "es_activo", "observaciones").
+		Valores(nombre, datos, esActivo, observaciones).
+		ObtenerID(&id).
+		Ejecutar()
+
+	return id, err
+}
+
+func modificarEnCosas(bd *BD, id int64, nombre string, esActivo bool, observaciones string) error {
+	mod := bd.
+		Modificar("cosasModificar").
+		Tabla("cosas").
+		Campos("nombre", "es_activo", "observaciones").
+		Valores(nombre, esActivo, observaciones).
+		Condicion("id = ? ", id)
+
+	// s, err := mod.SQL()
+	// fmt.Println("SQL:", s, err)
+	return mod.Ejecutar()
+
+	// return err
+}
+
+func seleccionarEnCosas(bd *BD) error {
+	var cosas = []struct {
+		ID            int64  `bdsql:"id"`
+		Nombre        string `bdsql:"nombre"`
+		Datos         string
+		EsActivo      bool   `bdsql:"es_activo"`
+		Observaciones string `bdsql:"observaciones"`
+	}{}
+
+	var cant, err = bd.Seleccionar("seleccionarEnCosas").Tabla("cosas").Campos("*").Resultado(&cosas).Ejecutar()
+	if err != nil {
+		fmt.Println("ERROR:", err)
+		return err
+	}
+	fmt.Println("Cantidad:", cant)
+	for _, cosa := range cosas {
+		fmt.Println(cosa)
+	}
+
+	return nil
+}
@@ -0,0 +1,332 @@
+/*
+Package bdsqltest permite probar el código que utiliza bdsql sin un motor de
+base de datos: ofrece un controlador (driver) de database/sql simulado en el
+cual se registran las sentencias esperadas y lo que cada una debe devolver
+(registros, un resultado o un error).
+
+Las sentencias se esperan en el orden en que se registran. Una sentencia que
+no coincide con la siguiente espera (texto o valores) devuelve un error y se
+informa como un error de la prueba al finalizar:
+
+	func TestObtenerPersona(t *testing.T) {
+		bd, ctrl := bdsqltest.Nuevo(t)
+		ctrl.Esperar("select id, nombre from personas where id = ?;").
+			ConValores(1).
+			DevolverFilas(bdsqltest.NuevasFilas("id", "nombre").Agregar(1, "Ana"))
+		ctrl.EsperarPatron(`^insert into personas`).
+			DevolverError(bdsqltest.ErrorMysql(1062, "Duplicate entry '1' for key 'PRIMARY'"))
+
+		// ... código que utiliza bd
+	}
+
+La apertura, la confirmación y la reversión de transacciones no requieren
+esperas. Las sentencias ejecutadas con EjecutarResultado() leen las
+advertencias del motor con 'show warnings;', que también debe esperarse.
+*/
+package bdsqltest
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fabianpallares/bdsql"
+	"github.com/go-sql-driver/mysql"
+)
+
+// Nuevo crea una base de datos conectada a un controlador simulado. Al
+// finalizar la prueba se cierra la base de datos y se informa como error
+// cualquier espera no cumplida o sentencia no esperada.
+func Nuevo(t testing.TB) (*bdsql.BD, *Controlador) {
+	var ctrl = &Controlador{}
+	return abrir(t, ctrl), ctrl
+}
+
+// abrir crea una base de datos conectada al controlador simulado y verifica
+// sus esperas al finalizar la prueba.
+func abrir(t testing.TB, ctrl *Controlador) *bdsql.BD {
+	var db = sql.OpenDB(conector{ctrl: ctrl})
+
+	t.Cleanup(func() {
+		db.Close()
+		if err := ctrl.Verificar(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	return bdsql.NuevaBD(db)
+}
+
+// Controlador representa el controlador (driver) simulado: mantiene las
+// sentencias esperadas en el orden en que se registraron.
+type Controlador struct {
+	mux sync.Mutex
+
+	esperas   []*Espera
+	siguiente int // posición de la próxima espera a cumplir
+
+	// errores almacena las sentencias no esperadas o que no coincidieron con
+	// la espera correspondiente
+	errores []string
+}
+
+// Esperar registra una sentencia esperada. El texto debe coincidir
+// exactamente con la sentencia ejecutada, sin considerar los espacios
+// repetidos ni los saltos de línea.
+func (c *Controlador) Esperar(sentencia string) *Espera {
+	var e = &Espera{sentencia: normalizar(sentencia)}
+	c.agregar(e)
+
+	return e
+}
+
+// EsperarPatron registra una sentencia esperada que debe coincidir con la
+// expresión regular recibida.
+func (c *Controlador) EsperarPatron(patron string) *Espera {
+	var e = &Espera{patron: regexp.MustCompile(patron)}
+	c.agregar(e)
+
+	return e
+}
+
+// Verificar devuelve un error si existen esperas no cumplidas o si se
+// ejecutaron sentencias no esperadas.
+func (c *Controlador) Verificar() error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	var mensajes = append([]string(nil), c.errores...)
+	for _, e := range c.esperas[c.siguiente:] {
+		mensajes = append(mensajes, fmt.Sprintf("espera no cumplida: %v", e))
+	}
+	if len(mensajes) != 0 {
+		return fmt.Errorf("bdsqltest: %v", strings.Join(mensajes, "; "))
+	}
+
+	return nil
+}
+
+func (c *Controlador) agregar(e *Espera) {
+	c.mux.Lock()
+	c.esperas = append(c.esperas, e)
+	c.mux.Unlock()
+}
+
+// cumplir busca la siguiente espera y verifica que coincida con la sentencia
+// ejecutada y sus valores.
+func (c *Controlador) cumplir(sentencia string, valores []driver.NamedValue) (*Espera, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	var err error
+	if c.siguiente == len(c.esperas) {
+		err = fmt.Errorf("sentencia no esperada: %q %v", sentencia, valoresDe(valores))
+	} else if e := c.esperas[c.siguiente]; !e.coincideSentencia(sentencia) {
+		err = fmt.Errorf("sentencia incorrecta: se esperaba %v y se ejecutó %q", e, sentencia)
+	} else if !e.coincidenValores(valores) {
+		err = fmt.Errorf("valores incorrectos en %q: se esperaban %v y se recibieron %v", sentencia, e.valores, valoresDe(valores))
+	} else {
+		c.siguiente++
+		return e, nil
+	}
+
+	c.errores = append(c.errores, err.Error())
+	return nil, fmt.Errorf("bdsqltest: %v", err)
+}
+
+// -----------------------------------------------------------------------------
+
+// Espera representa una sentencia esperada y lo que devuelve al ejecutarse.
+type Espera struct {
+	sentencia string         // texto exacto de la sentencia (normalizado)
+	patron    *regexp.Regexp // expresión regular de la sentencia
+
+	valores    []interface{}
+	conValores bool // verificar los valores recibidos por la sentencia
+
+	filas     *Filas
+	ultimoID  int64
+	afectados int64
+	err       error
+}
+
+// ConValores establece los valores que debe recibir la sentencia. Si no se
+// establecen, los valores no se verifican. El valor Cualquiera() coincide con
+// cualquier valor recibido.
+func (e *Espera) ConValores(valores ...interface{}) *Espera {
+	e.valores = valores
+	e.conValores = true
+
+	return e
+}
+
+// DevolverFilas establece los registros que devuelve la consulta.
+func (e *Espera) DevolverFilas(filas *Filas) *Espera {
+	e.filas = filas
+
+	return e
+}
+
+// DevolverResultado establece el resultado de una sentencia de escritura:
+// el último id insertado y la cantidad de registros afectados.
+func (e *Espera) DevolverResultado(ultimoID, afectados int64) *Espera {
+	e.ultimoID = ultimoID
+	e.afectados = afectados
+
+	return e
+}
+
+// DevolverError establece el error que devuelve la sentencia. Para simular
+// los errores del motor se utiliza ErrorMysql().
+func (e *Espera) DevolverError(err error) *Espera {
+	e.err = err
+
+	return e
+}
+
+// String devuelve la descripción de la espera.
+func (e *Espera) String() string {
+	if e.patron != nil {
+		return fmt.Sprintf("/%v/", e.patron)
+	}
+
+	return fmt.Sprintf("%q", e.sentencia)
+}
+
+func (e *Espera) coincideSentencia(sentencia string) bool {
+	if e.patron != nil {
+		return e.patron.MatchString(sentencia)
+	}
+
+	return e.sentencia == normalizar(sentencia)
+}
+
+func (e *Espera) coincidenValores(recibidos []driver.NamedValue) bool {
+	if !e.conValores {
+		return true
+	}
+	if len(e.valores) != len(recibidos) {
+		return false
+	}
+	for i, valor := range e.valores {
+		if _, ok := valor.(cualquiera); ok {
+			continue
+		}
+		esperado, err := convertirValor(valor)
+		if err != nil || !valoresIguales(esperado, recibidos[i].Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// -----------------------------------------------------------------------------
+
+// Filas representa los registros que devuelve una consulta.
+type Filas struct {
+	columnas []string
+	valores  [][]driver.Value
+	err      error
+}
+
+// NuevasFilas crea los registros de una consulta con las columnas recibidas.
+func NuevasFilas(columnas ...string) *Filas {
+	return &Filas{columnas: columnas}
+}
+
+// Agregar agrega un registro con los valores de cada columna.
+func (f *Filas) Agregar(valores ...interface{}) *Filas {
+	if f.err != nil {
+		return f
+	}
+	if len(valores) != len(f.columnas) {
+		f.err = fmt.Errorf("bdsqltest: el registro tiene %v valores y la consulta %v columnas", len(valores), len(f.columnas))
+		return f
+	}
+
+	var registro = make([]driver.Value, len(valores))
+	for i, valor := range valores {
+		v, err := driver.DefaultParameterConverter.ConvertValue(valor)
+		if err != nil {
+			f.err = fmt.Errorf("bdsqltest: valor no admitido en la columna %v: %v", f.columnas[i], err)
+			return f
+		}
+		registro[i] = v
+	}
+	f.valores = append(f.valores, registro)
+
+	return f
+}
+
+// -----------------------------------------------------------------------------
+
+type cualquiera struct{}
+
+// Cualquiera devuelve un valor que coincide con cualquier valor recibido por
+// la sentencia, por ejemplo un momento generado con time.Now().
+func Cualquiera() interface{} {
+	return cualquiera{}
+}
+
+// ErrorMysql crea un error del motor con el número y el mensaje recibidos,
+// tal como lo devuelve el controlador de Mysql/MariaDB.
+func ErrorMysql(numero uint16, mensaje string) error {
+	return &mysql.MySQLError{Number: numero, Message: mensaje}
+}
+
+// normalizar reemplaza los espacios repetidos y los saltos de línea de la
+// sentencia por un único espacio.
+func normalizar(sentencia string) string {
+	return strings.Join(strings.Fields(sentencia), " ")
+}
+
+func valoresDe(valores []driver.NamedValue) []driver.Value {
+	var vs = make([]driver.Value, len(valores))
+	for i, v := range valores {
+		vs[i] = v.Value
+	}
+
+	return vs
+}
+
+// convertirValor convierte el valor como el controlador de Mysql: los
+// enteros sin signo se conservan como uint64 (el conversor de database/sql
+// no admite los superiores a int64) y los demás valores se convierten con
+// el conversor de database/sql.
+func convertirValor(valor interface{}) (driver.Value, error) {
+	if _, ok := valor.(driver.Valuer); !ok && valor != nil {
+		switch v := reflect.ValueOf(valor); v.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return v.Uint(), nil
+		}
+	}
+
+	return driver.DefaultParameterConverter.ConvertValue(valor)
+}
+
+func valoresIguales(a, b driver.Value) bool {
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+		return ok && ta.Equal(tb)
+	}
+
+	return reflect.DeepEqual(normalizarEntero(a), normalizarEntero(b))
+}
+
+// normalizarEntero representa los enteros sin signo que admite int64 como
+// int64, para que un valor coincida sin importar con qué conversor se obtuvo.
+func normalizarEntero(valor driver.Value) driver.Value {
+	if v, ok := valor.(uint64); ok && v <= math.MaxInt64 {
+		return int64(v)
+	}
+
+	return valor
+}
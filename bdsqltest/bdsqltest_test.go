@@ -0,0 +1,166 @@
+package bdsqltest
+
+import (
+	"database/sql"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fabianpallares/bdsql"
+)
+
+type persona struct {
+	ID     int64     `bdsql:"id"`
+	Nombre string    `bdsql:"nombre"`
+	Alta   time.Time `bdsql:"alta"`
+}
+
+func TestSeleccionarEInsertar(t *testing.T) {
+	bd, ctrl := Nuevo(t)
+	alta := time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+	ctrl.Esperar("select id, nombre, alta from personas where id > ?;").
+		ConValores(10).
+		DevolverFilas(NuevasFilas("id", "nombre", "alta").Agregar(11, "Ana", alta).Agregar(12, "Luis", alta))
+	ctrl.EsperarPatron(`^insert into personas`).
+		ConValores("Eva", Cualquiera()).
+		DevolverResultado(13, 1)
+
+	var personas []persona
+	n, err := bd.Seleccionar("-").Tabla("personas").Campos("id", "nombre", "alta").
+		Condicion("id > ?", 10).Resultado(&personas).Ejecutar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || personas[1].Nombre != "Luis" || !personas[0].Alta.Equal(alta) {
+		t.Errorf("registros incorrectos: %v %+v", n, personas)
+	}
+
+	var id int64
+	err = bd.Insertar("personasInsertar").Tabla("personas").Campos("nombre", "alta").
+		Valores("Eva", time.Now()).ObtenerID(&id).Ejecutar()
+	if err != nil || id != 13 {
+		t.Errorf("inserción incorrecta: %v %v", id, err)
+	}
+}
+
+func TestErroresMysql(t *testing.T) {
+	bd, ctrl := Nuevo(t)
+	ctrl.Esperar("insert into personas (nombre) values (?);").
+		DevolverError(ErrorMysql(1062, "Duplicate entry 'Ana' for key 'nombre'"))
+	ctrl.Esperar("update personas set nombre = ? where id = ?;").
+		DevolverError(ErrorMysql(1146, "Table 'bdsql.personas' doesn't exist"))
+
+	err := bd.Insertar("-").Tabla("personas").Campos("nombre").Valores("Ana").Ejecutar()
+	if e, ok := bdsql.EsError(err); !ok || !e.EsEntradaDuplicada() {
+		t.Errorf("se esperaba el error de entrada duplicada: %v", err)
+	}
+	err = bd.Modificar("-").Tabla("personas").Campos("nombre").Valores("Ana").Condicion("id = ?", 1).Ejecutar()
+	if e, ok := bdsql.EsError(err); !ok || !e.EsTablaInexistente() {
+		t.Errorf("se esperaba el error de tabla inexistente: %v", err)
+	}
+}
+
+func TestTransaccionYSentenciaNoEsperada(t *testing.T) {
+	bd, ctrl := Nuevo(t)
+	ctrl.Esperar("delete from personas where id = ?;").ConValores(1).DevolverResultado(0, 1)
+
+	tx, err := bd.TxIniciar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tx.Eliminar("-").Tabla("personas").Condicion("id = ?", 1).Ejecutar()
+	if err = tx.TxFinalizar(err); err != nil {
+		t.Fatal(err)
+	}
+	if err = ctrl.Verificar(); err != nil {
+		t.Fatal(err)
+	}
+
+	// valores distintos a los esperados
+	ctrl.Esperar("delete from personas where id = ?;").ConValores(2)
+	err = bd.Eliminar("-").Tabla("personas").Condicion("id = ?", 3).Ejecutar()
+	if e, ok := bdsql.EsError(err); !ok || !e.EsErrorNoAtrapado() {
+		t.Errorf("se esperaba un error por valores incorrectos: %v", err)
+	}
+	if ctrl.Verificar() == nil {
+		t.Error("se esperaba la espera no cumplida")
+	}
+
+	// descartar los errores para que la prueba no falle al finalizar
+	ctrl.errores, ctrl.esperas, ctrl.siguiente = nil, nil, 0
+}
+
+func TestGrabarYReproducir(t *testing.T) {
+	archivo := t.TempDir() + "/personas.json"
+	alta := time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	// grabación sobre el controlador simulado en lugar de la base de datos real
+	var ctrl = &Controlador{}
+	ctrl.Esperar("select id, nombre, alta from personas where id > ?;").
+		DevolverFilas(NuevasFilas("id", "nombre", "alta").Agregar(11, []byte{0xff, 'A'}, alta))
+	ctrl.Esperar("insert into personas (nombre) values (?);").
+		DevolverError(ErrorMysql(1062, "Duplicate entry"))
+	var g = &grabador{real: conector{ctrl: ctrl}}
+	ejecutarPersonas(t, bdsql.NuevaBD(sql.OpenDB(g)))
+	if err := g.escribir(archivo); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctrl.Verificar(); err != nil {
+		t.Fatal(err)
+	}
+
+	// reproducción sin base de datos
+	ejecutarPersonas(t, Reproducir(t, archivo))
+}
+
+func TestGrabarYReproducirSinSigno(t *testing.T) {
+	archivo := t.TempDir() + "/sin_signo.json"
+
+	// el controlador conserva los enteros sin signo (como el de Mysql), incluso
+	// los superiores a int64
+	var ctrl = &Controlador{}
+	ctrl.Esperar("select id from personas where id in (?, ?);").
+		ConValores(5, uint64(math.MaxUint64)).
+		DevolverFilas(NuevasFilas("id").Agregar(5))
+	var g = &grabador{real: conector{ctrl: ctrl}}
+	ejecutarSinSigno(t, bdsql.NuevaBD(sql.OpenDB(g)))
+	if err := g.escribir(archivo); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctrl.Verificar(); err != nil {
+		t.Fatal(err)
+	}
+	if contenido, err := os.ReadFile(archivo); err != nil || !strings.Contains(string(contenido), `"entero_sin_signo"`) {
+		t.Errorf("grabación incorrecta: %s %v", contenido, err)
+	}
+
+	// reproducción sin base de datos
+	ejecutarSinSigno(t, Reproducir(t, archivo))
+}
+
+func ejecutarSinSigno(t *testing.T, bd *bdsql.BD) {
+	var registros []struct {
+		ID int64 `bdsql:"id"`
+	}
+	n, err := bd.Seleccionar("-").Tabla("personas").Campos("id").
+		Condicion("id in (?, ?)", uint(5), uint64(math.MaxUint64)).Resultado(&registros).Ejecutar()
+	if err != nil || n != 1 || registros[0].ID != 5 {
+		t.Errorf("registros incorrectos: %v %+v %v", n, registros, err)
+	}
+}
+
+func ejecutarPersonas(t *testing.T, bd *bdsql.BD) {
+	var personas []persona
+	n, err := bd.Seleccionar("-").Tabla("personas").Campos("id", "nombre", "alta").
+		Condicion("id > ?", 10).Resultado(&personas).Ejecutar()
+	if err != nil || n != 1 || personas[0].Nombre != "\xffA" || !personas[0].Alta.Equal(time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("registros incorrectos: %v %+v %v", n, personas, err)
+	}
+
+	err = bd.Insertar("-").Tabla("personas").Campos("nombre").Valores("Ana").Ejecutar()
+	if e, ok := bdsql.EsError(err); !ok || !e.EsEntradaDuplicada() {
+		t.Errorf("se esperaba el error de entrada duplicada: %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package bdsqltest
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+)
+
+// conector implementa driver.Connector y driver.Driver: todas las conexiones
+// del pool comparten el mismo controlador.
+type conector struct {
+	ctrl *Controlador
+}
+
+func (c conector) Connect(context.Context) (driver.Conn, error) {
+	return &conexion{ctrl: c.ctrl}, nil
+}
+
+func (c conector) Driver() driver.Driver {
+	return c
+}
+
+func (c conector) Open(string) (driver.Conn, error) {
+	return &conexion{ctrl: c.ctrl}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+type conexion struct {
+	ctrl *Controlador
+}
+
+func (c *conexion) Prepare(texto string) (driver.Stmt, error) {
+	return &sentencia{con: c, texto: texto}, nil
+}
+
+func (c *conexion) Close() error {
+	return nil
+}
+
+func (c *conexion) Begin() (driver.Tx, error) {
+	return transaccion{}, nil
+}
+
+func (c *conexion) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return transaccion{}, nil
+}
+
+// CheckNamedValue convierte los valores recibidos como el controlador de
+// Mysql: los enteros sin signo se conservan como uint64.
+func (c *conexion) CheckNamedValue(valor *driver.NamedValue) error {
+	v, err := convertirValor(valor.Value)
+	if err != nil {
+		return err
+	}
+	valor.Value = v
+
+	return nil
+}
+
+func (c *conexion) ExecContext(_ context.Context, sentencia string, valores []driver.NamedValue) (driver.Result, error) {
+	e, err := c.ctrl.cumplir(sentencia, valores)
+	if err != nil {
+		return nil, err
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	return resultado{ultimoID: e.ultimoID, afectados: e.afectados}, nil
+}
+
+func (c *conexion) QueryContext(_ context.Context, sentencia string, valores []driver.NamedValue) (driver.Rows, error) {
+	e, err := c.ctrl.cumplir(sentencia, valores)
+	if err != nil {
+		return nil, err
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+	if e.filas == nil {
+		return &filas{}, nil
+	}
+	if e.filas.err != nil {
+		return nil, e.filas.err
+	}
+
+	return &filas{columnas: e.filas.columnas, valores: e.filas.valores}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+// sentencia representa una sentencia preparada: la coincidencia con las
+// esperas se verifica en cada ejecución.
+type sentencia struct {
+	con   *conexion
+	texto string
+}
+
+func (s *sentencia) Close() error {
+	return nil
+}
+
+func (s *sentencia) NumInput() int {
+	return -1
+}
+
+func (s *sentencia) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("bdsqltest: Exec no admitido, se utiliza ExecContext")
+}
+
+func (s *sentencia) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("bdsqltest: Query no admitido, se utiliza QueryContext")
+}
+
+func (s *sentencia) ExecContext(ctx context.Context, valores []driver.NamedValue) (driver.Result, error) {
+	return s.con.ExecContext(ctx, s.texto, valores)
+}
+
+func (s *sentencia) QueryContext(ctx context.Context, valores []driver.NamedValue) (driver.Rows, error) {
+	return s.con.QueryContext(ctx, s.texto, valores)
+}
+
+// -----------------------------------------------------------------------------
+
+type transaccion struct{}
+
+func (transaccion) Commit() error   { return nil }
+func (transaccion) Rollback() error { return nil }
+
+type resultado struct {
+	ultimoID  int64
+	afectados int64
+}
+
+func (r resultado) LastInsertId() (int64, error) { return r.ultimoID, nil }
+func (r resultado) RowsAffected() (int64, error) { return r.afectados, nil }
+
+type filas struct {
+	columnas []string
+	valores  [][]driver.Value
+	posicion int
+}
+
+func (f *filas) Columns() []string {
+	return f.columnas
+}
+
+func (f *filas) Close() error {
+	return nil
+}
+
+func (f *filas) Next(destino []driver.Value) error {
+	if f.posicion == len(f.valores) {
+		return io.EOF
+	}
+	copy(destino, f.valores[f.posicion])
+	f.posicion++
+
+	return nil
+}
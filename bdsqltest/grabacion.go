@@ -0,0 +1,476 @@
+package bdsqltest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fabianpallares/bdsql"
+	"github.com/go-sql-driver/mysql"
+)
+
+// VariableGrabar es la variable de entorno que, al estar definida, hace que
+// Grabacion() ejecute las sentencias en la base de datos real y grabe el
+// archivo en lugar de reproducirlo.
+const VariableGrabar = "BDSQLTEST_GRABAR"
+
+// Grabacion devuelve una base de datos que reproduce las sentencias del
+// archivo recibido. Si la variable de entorno BDSQLTEST_GRABAR está definida,
+// se conecta a la base de datos real (dsn) y graba el archivo:
+//
+//	bd := bdsqltest.Grabacion(t, dsn, "testdata/personas.json")
+//
+// Así, las pruebas se ejecutan sin base de datos y los archivos se
+// actualizan localmente con 'BDSQLTEST_GRABAR=1 go test ./...'.
+func Grabacion(t testing.TB, dsn, archivo string) *bdsql.BD {
+	if os.Getenv(VariableGrabar) != "" {
+		return Grabar(t, dsn, archivo)
+	}
+
+	return Reproducir(t, archivo)
+}
+
+// Grabar se conecta a la base de datos real y graba en el archivo (JSON)
+// cada sentencia ejecutada, con sus valores y lo que devolvió: registros,
+// resultado o error. El archivo se escribe al finalizar la prueba.
+func Grabar(t testing.TB, dsn, archivo string) *bdsql.BD {
+	conReal, err := mysql.MySQLDriver{}.OpenConnector(dsn)
+	if err != nil {
+		t.Fatalf("bdsqltest: no es posible conectar con la base de datos: %v", err)
+	}
+
+	var g = &grabador{real: conReal}
+	var db = sql.OpenDB(g)
+	t.Cleanup(func() {
+		db.Close()
+		if err := g.escribir(archivo); err != nil {
+			t.Error(err)
+		}
+	})
+
+	return bdsql.NuevaBD(db)
+}
+
+// Reproducir devuelve una base de datos que responde, sin conexión, con lo
+// grabado en el archivo. Las sentencias deben ejecutarse en el mismo orden y
+// con los mismos valores que en la grabación: una sentencia no grabada
+// devuelve un error y se informa como un error de la prueba.
+func Reproducir(t testing.TB, archivo string) *bdsql.BD {
+	contenido, err := os.ReadFile(archivo)
+	if err != nil {
+		t.Fatalf("bdsqltest: no es posible leer la grabación: %v", err)
+	}
+	var gr grabacion
+	if err := json.Unmarshal(contenido, &gr); err != nil {
+		t.Fatalf("bdsqltest: grabación incorrecta en %v: %v", archivo, err)
+	}
+
+	var ctrl = &Controlador{}
+	for i, s := range gr.Sentencias {
+		e, err := s.espera()
+		if err != nil {
+			t.Fatalf("bdsqltest: grabación incorrecta en %v (sentencia %v): %v", archivo, i+1, err)
+		}
+		ctrl.agregar(e)
+	}
+
+	return abrir(t, ctrl)
+}
+
+// -----------------------------------------------------------------------------
+
+// grabacion representa el contenido del archivo de grabación.
+type grabacion struct {
+	Sentencias []sentenciaGrabada `json:"sentencias"`
+}
+
+type sentenciaGrabada struct {
+	Sentencia string           `json:"sentencia"`
+	Valores   []valorGrabado   `json:"valores,omitempty"`
+	Columnas  []string         `json:"columnas,omitempty"`
+	Filas     [][]valorGrabado `json:"filas,omitempty"`
+	UltimoID  int64            `json:"ultimoID,omitempty"`
+	Afectados int64            `json:"afectados,omitempty"`
+	Error     *errorGrabado    `json:"error,omitempty"`
+}
+
+type errorGrabado struct {
+	Numero  uint16 `json:"numero,omitempty"` // número del error del motor (0 si no es un error del motor)
+	Mensaje string `json:"mensaje"`
+}
+
+// valorGrabado conserva el tipo del valor, que JSON no distingue.
+type valorGrabado struct {
+	Tipo  string `json:"tipo"` // nulo, entero, entero_sin_signo, decimal, logico, texto, bytes, binario o momento
+	Valor string `json:"valor,omitempty"`
+}
+
+// espera convierte la sentencia grabada en la espera del controlador.
+func (s sentenciaGrabada) espera() (*Espera, error) {
+	var e = &Espera{sentencia: normalizar(s.Sentencia), ultimoID: s.UltimoID, afectados: s.Afectados}
+
+	e.conValores = true
+	for _, v := range s.Valores {
+		valor, err := v.valor()
+		if err != nil {
+			return nil, err
+		}
+		e.valores = append(e.valores, valor)
+	}
+
+	if s.Columnas != nil {
+		e.filas = NuevasFilas(s.Columnas...)
+		for _, fila := range s.Filas {
+			var registro = make([]driver.Value, len(fila))
+			for i, v := range fila {
+				valor, err := v.valor()
+				if err != nil {
+					return nil, err
+				}
+				registro[i] = valor
+			}
+			e.filas.valores = append(e.filas.valores, registro)
+		}
+	}
+
+	if s.Error != nil {
+		if s.Error.Numero != 0 {
+			e.err = ErrorMysql(s.Error.Numero, s.Error.Mensaje)
+		} else {
+			e.err = errors.New(s.Error.Mensaje)
+		}
+	}
+
+	return e, nil
+}
+
+func grabarValor(valor driver.Value) valorGrabado {
+	switch v := valor.(type) {
+	case nil:
+		return valorGrabado{Tipo: "nulo"}
+	case int64:
+		return valorGrabado{Tipo: "entero", Valor: strconv.FormatInt(v, 10)}
+	case uint64:
+		// el controlador de Mysql conserva los enteros sin signo
+		return valorGrabado{Tipo: "entero_sin_signo", Valor: strconv.FormatUint(v, 10)}
+	case float64:
+		return valorGrabado{Tipo: "decimal", Valor: strconv.FormatFloat(v, 'g', -1, 64)}
+	case bool:
+		return valorGrabado{Tipo: "logico", Valor: strconv.FormatBool(v)}
+	case string:
+		return valorGrabado{Tipo: "texto", Valor: v}
+	case []byte:
+		if utf8.Valid(v) {
+			return valorGrabado{Tipo: "bytes", Valor: string(v)}
+		}
+		return valorGrabado{Tipo: "binario", Valor: base64.StdEncoding.EncodeToString(v)}
+	case time.Time:
+		return valorGrabado{Tipo: "momento", Valor: v.Format(time.RFC3339Nano)}
+	default:
+		return valorGrabado{Tipo: "texto", Valor: fmt.Sprint(v)}
+	}
+}
+
+func (v valorGrabado) valor() (driver.Value, error) {
+	switch v.Tipo {
+	case "nulo":
+		return nil, nil
+	case "entero":
+		return strconv.ParseInt(v.Valor, 10, 64)
+	case "entero_sin_signo":
+		return strconv.ParseUint(v.Valor, 10, 64)
+	case "decimal":
+		return strconv.ParseFloat(v.Valor, 64)
+	case "logico":
+		return strconv.ParseBool(v.Valor)
+	case "texto":
+		return v.Valor, nil
+	case "bytes":
+		return []byte(v.Valor), nil
+	case "binario":
+		return base64.StdEncoding.DecodeString(v.Valor)
+	case "momento":
+		return time.Parse(time.RFC3339Nano, v.Valor)
+	default:
+		return nil, fmt.Errorf("tipo de valor desconocido: %v", v.Tipo)
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// grabador implementa driver.Connector sobre el controlador real y registra
+// cada sentencia ejecutada por sus conexiones.
+type grabador struct {
+	real driver.Connector
+
+	mux        sync.Mutex
+	sentencias []sentenciaGrabada
+}
+
+func (g *grabador) Connect(ctx context.Context) (driver.Conn, error) {
+	con, err := g.real.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conexionGrabada{real: con, g: g}, nil
+}
+
+func (g *grabador) Driver() driver.Driver {
+	return g.real.Driver()
+}
+
+func (g *grabador) registrar(s sentenciaGrabada, err error) {
+	if err != nil {
+		s.Error = &errorGrabado{Mensaje: err.Error()}
+		if errMysql, ok := err.(*mysql.MySQLError); ok {
+			s.Error.Numero, s.Error.Mensaje = errMysql.Number, errMysql.Message
+		}
+	}
+
+	g.mux.Lock()
+	g.sentencias = append(g.sentencias, s)
+	g.mux.Unlock()
+}
+
+func (g *grabador) escribir(archivo string) error {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+
+	contenido, err := json.MarshalIndent(grabacion{Sentencias: g.sentencias}, "", "\t")
+	if err != nil {
+		return fmt.Errorf("bdsqltest: no es posible generar la grabación: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(archivo), 0755); err != nil {
+		return fmt.Errorf("bdsqltest: no es posible escribir la grabación: %v", err)
+	}
+	if err := os.WriteFile(archivo, append(contenido, '\n'), 0644); err != nil {
+		return fmt.Errorf("bdsqltest: no es posible escribir la grabación: %v", err)
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+type conexionGrabada struct {
+	real driver.Conn
+	g    *grabador
+}
+
+func (c *conexionGrabada) Prepare(texto string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), texto)
+}
+
+func (c *conexionGrabada) PrepareContext(ctx context.Context, texto string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	var err error
+	if p, ok := c.real.(driver.ConnPrepareContext); ok {
+		stmt, err = p.PrepareContext(ctx, texto)
+	} else {
+		stmt, err = c.real.Prepare(texto)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &preparadaGrabada{real: stmt, texto: texto, g: c.g}, nil
+}
+
+func (c *conexionGrabada) Close() error {
+	return c.real.Close()
+}
+
+func (c *conexionGrabada) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conexionGrabada) BeginTx(ctx context.Context, opciones driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.real.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opciones)
+	}
+
+	return c.real.Begin()
+}
+
+func (c *conexionGrabada) ResetSession(ctx context.Context) error {
+	if r, ok := c.real.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+
+	return nil
+}
+
+func (c *conexionGrabada) CheckNamedValue(valor *driver.NamedValue) error {
+	if v, ok := c.real.(driver.NamedValueChecker); ok {
+		return v.CheckNamedValue(valor)
+	}
+
+	return driver.ErrSkip
+}
+
+func (c *conexionGrabada) ExecContext(ctx context.Context, texto string, valores []driver.NamedValue) (driver.Result, error) {
+	e, ok := c.real.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	res, err := e.ExecContext(ctx, texto, valores)
+	if err == driver.ErrSkip {
+		// la sentencia se prepara y se graba al ejecutarse
+		return nil, err
+	}
+
+	return grabarResultado(c.g, texto, valores, res, err)
+}
+
+func (c *conexionGrabada) QueryContext(ctx context.Context, texto string, valores []driver.NamedValue) (driver.Rows, error) {
+	q, ok := c.real.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	filas, err := q.QueryContext(ctx, texto, valores)
+	if err == driver.ErrSkip {
+		return nil, err
+	}
+
+	return grabarFilas(c.g, texto, valores, filas, err)
+}
+
+// -----------------------------------------------------------------------------
+
+// preparadaGrabada representa una sentencia preparada sobre el controlador
+// real, que se graba en cada ejecución.
+type preparadaGrabada struct {
+	real  driver.Stmt
+	texto string
+	g     *grabador
+}
+
+func (s *preparadaGrabada) Close() error {
+	return s.real.Close()
+}
+
+func (s *preparadaGrabada) NumInput() int {
+	return s.real.NumInput()
+}
+
+func (s *preparadaGrabada) Exec(valores []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), nombrados(valores))
+}
+
+func (s *preparadaGrabada) Query(valores []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), nombrados(valores))
+}
+
+func (s *preparadaGrabada) CheckNamedValue(valor *driver.NamedValue) error {
+	if v, ok := s.real.(driver.NamedValueChecker); ok {
+		return v.CheckNamedValue(valor)
+	}
+
+	return driver.ErrSkip
+}
+
+func (s *preparadaGrabada) ExecContext(ctx context.Context, valores []driver.NamedValue) (driver.Result, error) {
+	var res driver.Result
+	var err error
+	if e, ok := s.real.(driver.StmtExecContext); ok {
+		res, err = e.ExecContext(ctx, valores)
+	} else {
+		res, err = s.real.Exec(valoresDe(valores))
+	}
+
+	return grabarResultado(s.g, s.texto, valores, res, err)
+}
+
+func (s *preparadaGrabada) QueryContext(ctx context.Context, valores []driver.NamedValue) (driver.Rows, error) {
+	var filas driver.Rows
+	var err error
+	if q, ok := s.real.(driver.StmtQueryContext); ok {
+		filas, err = q.QueryContext(ctx, valores)
+	} else {
+		filas, err = s.real.Query(valoresDe(valores))
+	}
+
+	return grabarFilas(s.g, s.texto, valores, filas, err)
+}
+
+// -----------------------------------------------------------------------------
+
+// grabarResultado graba el resultado de una sentencia de escritura.
+func grabarResultado(g *grabador, texto string, valores []driver.NamedValue, res driver.Result, err error) (driver.Result, error) {
+	var s = sentenciaGrabada{Sentencia: texto, Valores: grabarValores(valoresDe(valores))}
+	if err != nil {
+		g.registrar(s, err)
+		return nil, err
+	}
+
+	s.UltimoID, _ = res.LastInsertId()
+	s.Afectados, _ = res.RowsAffected()
+	g.registrar(s, nil)
+
+	return resultado{ultimoID: s.UltimoID, afectados: s.Afectados}, nil
+}
+
+// grabarFilas lee todos los registros de la consulta, los graba y los
+// devuelve desde la memoria.
+func grabarFilas(g *grabador, texto string, valores []driver.NamedValue, reales driver.Rows, err error) (driver.Rows, error) {
+	var s = sentenciaGrabada{Sentencia: texto, Valores: grabarValores(valoresDe(valores))}
+	if err != nil {
+		g.registrar(s, err)
+		return nil, err
+	}
+	defer reales.Close()
+
+	var f = &filas{columnas: reales.Columns()}
+	s.Columnas = f.columnas
+	for {
+		var registro = make([]driver.Value, len(f.columnas))
+		if err := reales.Next(registro); err == io.EOF {
+			break
+		} else if err != nil {
+			g.registrar(s, err)
+			return nil, err
+		}
+		// el controlador real puede reutilizar la memoria de los bytes
+		for i, v := range registro {
+			if b, ok := v.([]byte); ok {
+				registro[i] = append([]byte(nil), b...)
+			}
+		}
+		f.valores = append(f.valores, registro)
+		s.Filas = append(s.Filas, grabarValores(registro))
+	}
+	g.registrar(s, nil)
+
+	return f, nil
+}
+
+func grabarValores(valores []driver.Value) []valorGrabado {
+	var gs = make([]valorGrabado, len(valores))
+	for i, v := range valores {
+		gs[i] = grabarValor(v)
+	}
+
+	return gs
+}
+
+func nombrados(valores []driver.Value) []driver.NamedValue {
+	var nvs = make([]driver.NamedValue, len(valores))
+	for i, v := range valores {
+		nvs[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+
+	return nvs
+}
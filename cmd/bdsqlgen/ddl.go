@@ -0,0 +1,403 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fabianpallares/bdsql"
+)
+
+// leerDDL obtiene las tablas de las sentencias 'create table' de los
+// archivos '.sql' del directorio recibido, leídos en orden alfabético. Las
+// demás sentencias se ignoran.
+func leerDDL(directorio string) ([]bdsql.Tabla, error) {
+	archivos, err := filepath.Glob(filepath.Join(directorio, "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(archivos)
+
+	var tablas []bdsql.Tabla
+	for _, archivo := range archivos {
+		contenido, err := os.ReadFile(archivo)
+		if err != nil {
+			return nil, err
+		}
+		tablas = append(tablas, analizarDDL(string(contenido))...)
+	}
+	sort.Slice(tablas, func(i, j int) bool { return tablas[i].Nombre < tablas[j].Nombre })
+
+	return tablas, nil
+}
+
+// simbolo representa un elemento léxico de una sentencia SQL.
+type simbolo struct {
+	texto   string
+	esTexto bool // cadena de texto entre comillas simples o dobles
+}
+
+// es informa si el símbolo es la palabra o el signo recibido (sin
+// distinguir mayúsculas).
+func (s simbolo) es(palabra string) bool {
+	return !s.esTexto && strings.EqualFold(s.texto, palabra)
+}
+
+// analizarDDL obtiene las tablas de las sentencias 'create table' del
+// contenido recibido.
+func analizarDDL(contenido string) []bdsql.Tabla {
+	var simbolos = separarSimbolos(contenido)
+	var tablas []bdsql.Tabla
+	for i := 0; i < len(simbolos); i++ {
+		if !simbolos[i].es("create") {
+			continue
+		}
+		var j = i + 1
+		if j < len(simbolos) && simbolos[j].es("temporary") {
+			j++
+		}
+		if j >= len(simbolos) || !simbolos[j].es("table") {
+			continue
+		}
+		j++
+		if j+2 < len(simbolos) && simbolos[j].es("if") && simbolos[j+1].es("not") && simbolos[j+2].es("exists") {
+			j += 3
+		}
+		nombre, j := leerNombre(simbolos, j)
+		if nombre == "" || j >= len(simbolos) || !simbolos[j].es("(") {
+			continue
+		}
+
+		var fin = cierre(simbolos, j)
+		var tabla = bdsql.Tabla{Nombre: nombre}
+		for _, definicion := range separarPorComas(simbolos[j+1 : fin]) {
+			analizarDefinicion(&tabla, definicion)
+		}
+		// las columnas de la clave primaria no admiten valores nulos
+		for _, columna := range tabla.ClavePrimaria {
+			for k := range tabla.Columnas {
+				if strings.EqualFold(tabla.Columnas[k].Nombre, columna) {
+					tabla.Columnas[k].Nulo = false
+				}
+			}
+		}
+		tablas = append(tablas, tabla)
+		i = fin
+	}
+
+	return tablas
+}
+
+// analizarDefinicion incorpora a la tabla una definición de columna, clave o
+// índice de la sentencia 'create table'.
+func analizarDefinicion(tabla *bdsql.Tabla, s []simbolo) {
+	if len(s) == 0 {
+		return
+	}
+
+	// restricción con nombre: 'constraint <nombre> ...'
+	var nombreRestriccion string
+	if s[0].es("constraint") {
+		if len(s) > 1 && !s[1].es("primary") && !s[1].es("unique") && !s[1].es("foreign") && !s[1].es("check") {
+			nombreRestriccion = s[1].texto
+			s = s[2:]
+		} else {
+			s = s[1:]
+		}
+		if len(s) == 0 {
+			return
+		}
+	}
+
+	switch {
+	case s[0].es("primary"):
+		tabla.ClavePrimaria = leerColumnas(s)
+	case s[0].es("unique"), s[0].es("key"), s[0].es("index"):
+		var indice = bdsql.Indice{Unico: s[0].es("unique"), Nombre: nombreRestriccion}
+		var i = 1
+		if i < len(s) && (s[i].es("key") || s[i].es("index")) {
+			i++
+		}
+		if i < len(s) && !s[i].es("(") {
+			indice.Nombre = s[i].texto
+		}
+		indice.Columnas = leerColumnas(s)
+		if indice.Nombre == "" && len(indice.Columnas) > 0 {
+			indice.Nombre = indice.Columnas[0]
+		}
+		tabla.Indices = append(tabla.Indices, indice)
+	case s[0].es("foreign"):
+		var cf = bdsql.ClaveForanea{Nombre: nombreRestriccion}
+		var i = posicion(s, "(")
+		if i < 0 {
+			return
+		}
+		var fin = cierre(s, i)
+		cf.Columnas = leerColumnas(s[i:])
+		var ref = posicion(s[fin:], "references")
+		if ref < 0 {
+			return
+		}
+		var j int
+		cf.TablaReferencia, j = leerNombre(s, fin+ref+1)
+		cf.ColumnasReferencia = leerColumnas(s[j:])
+		for k := j; k+2 < len(s); k++ {
+			if s[k].es("on") && s[k+1].es("delete") {
+				cf.AlEliminar = leerAccion(s[k+2:])
+			}
+			if s[k].es("on") && s[k+1].es("update") {
+				cf.AlModificar = leerAccion(s[k+2:])
+			}
+		}
+		tabla.ClavesForaneas = append(tabla.ClavesForaneas, cf)
+	case s[0].es("fulltext"), s[0].es("spatial"), s[0].es("check"), s[0].es("period"):
+		// sin efecto en las estructuras generadas
+	default:
+		analizarColumna(tabla, s)
+	}
+}
+
+// analizarColumna incorpora a la tabla la definición de una columna.
+func analizarColumna(tabla *bdsql.Tabla, s []simbolo) {
+	if len(s) < 2 {
+		return
+	}
+
+	var c = bdsql.Columna{Nombre: s[0].texto, Nulo: true}
+	c.TipoDeDato, c.Tipo = tipoDeDato(strings.ToLower(s[1].texto))
+	var i = 2
+	if i < len(s) && s[i].es("(") {
+		var fin = cierre(s, i)
+		var argumentos []string
+		for _, a := range separarPorComas(s[i+1 : fin]) {
+			var partes []string
+			for _, p := range a {
+				partes = append(partes, p.texto)
+			}
+			argumentos = append(argumentos, strings.Join(partes, " "))
+		}
+		c.Tipo += "(" + strings.Join(argumentos, ",") + ")"
+		i = fin + 1
+	}
+
+	for ; i < len(s); i++ {
+		switch {
+		case s[i].es("unsigned"):
+			c.Tipo += " unsigned"
+		case s[i].es("not") && i+1 < len(s) && s[i+1].es("null"):
+			c.Nulo = false
+			i++
+		case s[i].es("null"):
+			c.Nulo = true
+		case s[i].es("auto_increment"):
+			c.AutoIncremental = true
+		case s[i].es("default") && i+1 < len(s):
+			i++
+			c.PorDefecto.Valid = !s[i].es("null")
+			c.PorDefecto.String = s[i].texto
+			if (s[i].es("-") || s[i].es("+")) && i+1 < len(s) {
+				// número con signo
+				i++
+				c.PorDefecto.String += s[i].texto
+			}
+			if i+2 < len(s) && s[i+1].es(".") {
+				// número con decimales
+				c.PorDefecto.String += "." + s[i+2].texto
+				i += 2
+			}
+			if i+1 < len(s) && s[i+1].es("(") {
+				// función: current_timestamp(), etc.
+				var fin = cierre(s, i+1)
+				c.PorDefecto.String += "()"
+				i = fin
+			}
+		case s[i].es("primary") && i+1 < len(s) && s[i+1].es("key"):
+			c.Nulo = false
+			tabla.ClavePrimaria = []string{c.Nombre}
+			i++
+		case s[i].es("unique"):
+			tabla.Indices = append(tabla.Indices, bdsql.Indice{Nombre: c.Nombre, Columnas: []string{c.Nombre}, Unico: true})
+		}
+	}
+	// 'serial' es un alias de 'bigint unsigned not null auto_increment unique'
+	if c.TipoDeDato == "bigint" && strings.EqualFold(s[1].texto, "serial") {
+		c.Tipo, c.Nulo, c.AutoIncremental = "bigint unsigned", false, true
+	}
+
+	tabla.Columnas = append(tabla.Columnas, c)
+}
+
+// tipoDeDato devuelve el tipo de dato y el tipo de la columna a partir del
+// tipo recibido en la definición, resolviendo los sinónimos.
+func tipoDeDato(tipo string) (string, string) {
+	switch tipo {
+	case "integer":
+		return "int", "int"
+	case "bool", "boolean":
+		return "tinyint", "tinyint(1)"
+	case "dec", "numeric", "fixed":
+		return "decimal", "decimal"
+	case "real":
+		return "double", "double"
+	case "character":
+		return "char", "char"
+	case "serial":
+		return "bigint", "bigint"
+	}
+
+	return tipo, tipo
+}
+
+// leerNombre lee un nombre (opcionalmente calificado: 'base.tabla') desde la
+// posición recibida. Devuelve el nombre sin calificar y la posición
+// siguiente.
+func leerNombre(s []simbolo, i int) (string, int) {
+	if i >= len(s) {
+		return "", i
+	}
+	var nombre = s[i].texto
+	for i+2 < len(s) && s[i+1].es(".") {
+		nombre = s[i+2].texto
+		i += 2
+	}
+
+	return nombre, i + 1
+}
+
+// leerColumnas devuelve las columnas de la primera lista entre paréntesis:
+// '(a, b(10), c desc)' → a, b, c.
+func leerColumnas(s []simbolo) []string {
+	var i = posicion(s, "(")
+	if i < 0 {
+		return nil
+	}
+
+	var columnas []string
+	for _, definicion := range separarPorComas(s[i+1 : cierre(s, i)]) {
+		if len(definicion) > 0 {
+			columnas = append(columnas, definicion[0].texto)
+		}
+	}
+
+	return columnas
+}
+
+// leerAccion devuelve la acción de una clave foránea ('cascade',
+// 'set null', 'no action', etc.) en mayúsculas, como la informa
+// 'information_schema'.
+func leerAccion(s []simbolo) string {
+	if len(s) >= 2 && (s[0].es("set") || s[0].es("no")) {
+		return strings.ToUpper(s[0].texto + " " + s[1].texto)
+	}
+	if len(s) >= 1 {
+		return strings.ToUpper(s[0].texto)
+	}
+
+	return ""
+}
+
+// posicion devuelve la posición del primer símbolo que es la palabra o el
+// signo recibido, o -1 si no existe.
+func posicion(s []simbolo, palabra string) int {
+	for i := range s {
+		if s[i].es(palabra) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// cierre devuelve la posición del paréntesis que cierra al paréntesis de la
+// posición recibida (o el último símbolo si no se cierra).
+func cierre(s []simbolo, abre int) int {
+	var nivel int
+	for i := abre; i < len(s); i++ {
+		switch {
+		case s[i].es("("):
+			nivel++
+		case s[i].es(")"):
+			nivel--
+			if nivel == 0 {
+				return i
+			}
+		}
+	}
+
+	return len(s) - 1
+}
+
+// separarPorComas separa los símbolos por las comas que no se encuentran
+// entre paréntesis.
+func separarPorComas(s []simbolo) [][]simbolo {
+	var partes [][]simbolo
+	var nivel, desde int
+	for i := range s {
+		switch {
+		case s[i].es("("):
+			nivel++
+		case s[i].es(")"):
+			nivel--
+		case s[i].es(",") && nivel == 0:
+			partes = append(partes, s[desde:i])
+			desde = i + 1
+		}
+	}
+
+	return append(partes, s[desde:])
+}
+
+// separarSimbolos separa el contenido en símbolos, descartando los
+// comentarios. Los identificadores entre acentos graves se devuelven sin
+// ellos y las cadenas de texto sin comillas.
+func separarSimbolos(contenido string) []simbolo {
+	var simbolos []simbolo
+	for i := 0; i < len(contenido); i++ {
+		var c = contenido[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		case c == '#' || strings.HasPrefix(contenido[i:], "-- ") || strings.HasPrefix(contenido[i:], "--\n"):
+			for ; i < len(contenido) && contenido[i] != '\n'; i++ {
+			}
+		case strings.HasPrefix(contenido[i:], "/*"):
+			if fin := strings.Index(contenido[i+2:], "*/"); fin >= 0 {
+				i += fin + 3
+			} else {
+				i = len(contenido)
+			}
+		case c == '\'' || c == '"' || c == '`':
+			var texto strings.Builder
+			for i++; i < len(contenido); i++ {
+				if contenido[i] == '\\' && c != '`' && i+1 < len(contenido) {
+					i++
+				} else if contenido[i] == c {
+					// comilla duplicada: forma parte del texto
+					if i+1 < len(contenido) && contenido[i+1] == c {
+						i++
+					} else {
+						break
+					}
+				}
+				texto.WriteByte(contenido[i])
+			}
+			simbolos = append(simbolos, simbolo{texto: texto.String(), esTexto: c != '`'})
+		case esCaracterDeNombre(c):
+			var desde = i
+			for i+1 < len(contenido) && esCaracterDeNombre(contenido[i+1]) {
+				i++
+			}
+			simbolos = append(simbolos, simbolo{texto: contenido[desde : i+1]})
+		default:
+			simbolos = append(simbolos, simbolo{texto: string(c)})
+		}
+	}
+
+	return simbolos
+}
+
+// esCaracterDeNombre informa si el byte puede formar parte de un nombre o un
+// número sin comillas (se incluyen los bytes de los caracteres UTF-8).
+func esCaracterDeNombre(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"strings"
+	"unicode"
+
+	"github.com/fabianpallares/bdsql"
+)
+
+// campo representa un campo de la estructura generada para una columna.
+type campo struct {
+	nombre  string // nombre del campo de la estructura
+	tipo    string // tipo de Go del campo
+	columna bdsql.Columna
+}
+
+// generador genera el código de las estructuras y los repositorios.
+type generador struct {
+	paquete  string
+	singular bool // nombrar las estructuras en singular
+}
+
+// generar devuelve el código fuente (con formato) de la estructura y el
+// repositorio de la tabla.
+func (g generador) generar(tabla bdsql.Tabla) ([]byte, error) {
+	var estructura = nombreGo(tabla.Nombre)
+	if g.singular {
+		estructura = singular(estructura)
+	}
+	var repositorio = "Repositorio" + estructura
+
+	var campos []campo
+	var usaTime, usaSQL bool
+	for _, c := range tabla.Columnas {
+		if c.AutoIncremental {
+			// la columna autoincremental siempre tiene valor
+			c.Nulo = false
+		}
+		var tipo = tipoGo(c)
+		usaTime = usaTime || strings.Contains(tipo, "time.")
+		usaSQL = usaSQL || strings.HasPrefix(tipo, "sql.")
+		campos = append(campos, campo{nombre: nombreGo(c.Nombre), tipo: tipo, columna: c})
+	}
+	var clave = camposDeClave(campos, tabla.ClavePrimaria)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by bdsqlgen. DO NOT EDIT.\n\npackage %v\n\nimport (\n", g.paquete)
+	if usaSQL {
+		b.WriteString("\t\"database/sql\"\n")
+	}
+	if usaTime {
+		b.WriteString("\t\"time\"\n")
+	}
+	b.WriteString("\n\t\"github.com/fabianpallares/bdsql\"\n)\n\n")
+
+	// estructura
+	fmt.Fprintf(&b, "// %v representa un registro de la tabla '%v'.\ntype %v struct {\n", estructura, tabla.Nombre, estructura)
+	for _, c := range campos {
+		fmt.Fprintf(&b, "\t%v %v `bdsql:\"%v\"`\n", c.nombre, c.tipo, c.columna.Nombre)
+	}
+	b.WriteString("}\n\n")
+
+	var columnas = "columnas" + estructura
+	fmt.Fprintf(&b, "// %v contiene las columnas de la tabla '%v'.\nvar %v = []string{", columnas, tabla.Nombre, columnas)
+	for i, c := range campos {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%q", c.columna.Nombre)
+	}
+	b.WriteString("}\n\n")
+
+	// repositorio
+	fmt.Fprintf(&b, "// %v contiene las sentencias de la tabla '%v'.\ntype %v struct {\n\tej bdsql.Ejecutor\n}\n\n", repositorio, tabla.Nombre, repositorio)
+	fmt.Fprintf(&b, "// Nuevo%v crea el repositorio de la tabla '%v' sobre una base de\n// datos o una transacción.\nfunc Nuevo%v(ej bdsql.Ejecutor) *%v {\n\treturn &%v{ej: ej}\n}\n\n", repositorio, tabla.Nombre, repositorio, repositorio, repositorio)
+
+	var nombreSentencia = func(operacion string) string {
+		return fmt.Sprintf("bdsqlgen.%v.%v", tabla.Nombre, operacion)
+	}
+
+	// condición y parámetros de la clave primaria
+	var condiciones, parametros, argumentos, valoresClave []string
+	for _, c := range clave {
+		var p = nombreParametro(c.nombre)
+		condiciones = append(condiciones, c.columna.Nombre+" = ?")
+		parametros = append(parametros, p+" "+c.tipo)
+		argumentos = append(argumentos, p)
+		valoresClave = append(valoresClave, "r."+c.nombre)
+	}
+	var condicion = strings.Join(condiciones, " and ")
+
+	if len(clave) > 0 {
+		fmt.Fprintf(&b, `// Obtener obtiene el registro por su clave primaria. El valor lógico informa
+// si el registro existe.
+func (repo *%[1]v) Obtener(%[2]v) (%[3]v, bool, error) {
+	var registros []%[3]v
+	_, err := repo.ej.
+		Seleccionar(%[4]q).
+		Tabla(%[5]q).
+		Campos(%[6]v...).
+		Condicion(%[7]q, %[8]v).
+		Resultado(&registros).
+		Ejecutar()
+	if err != nil || len(registros) == 0 {
+		return %[3]v{}, false, err
+	}
+
+	return registros[0], true, nil
+}
+
+`, repositorio, strings.Join(parametros, ", "), estructura, nombreSentencia("obtener"), tabla.Nombre, columnas, condicion, strings.Join(argumentos, ", "))
+	}
+
+	fmt.Fprintf(&b, `// Listar obtiene los registros que cumplen la condición, ordenados por los
+// campos recibidos. Una condición vacía obtiene todos los registros.
+func (repo *%[1]v) Listar(orden []string, condicion string, valores ...interface{}) ([]%[2]v, error) {
+	var registros []%[2]v
+	sel := repo.ej.
+		Seleccionar("-").
+		Tabla(%[3]q).
+		Campos(%[4]v...).
+		OrdenarPor(orden...).
+		Resultado(&registros)
+	if condicion != "" {
+		sel.Condicion(condicion, valores...)
+	}
+	if _, err := sel.Ejecutar(); err != nil {
+		return nil, err
+	}
+
+	return registros, nil
+}
+
+`, repositorio, estructura, tabla.Nombre, columnas)
+
+	// insertar: se omite la columna autoincremental, cuyo valor se asigna
+	// al registro
+	var camposInsertar, valoresInsertar []string
+	var autoIncremental *campo
+	for i, c := range campos {
+		if c.columna.AutoIncremental {
+			autoIncremental = &campos[i]
+			continue
+		}
+		camposInsertar = append(camposInsertar, fmt.Sprintf("%q", c.columna.Nombre))
+		valoresInsertar = append(valoresInsertar, "r."+c.nombre)
+	}
+	if len(camposInsertar) > 0 {
+		fmt.Fprintf(&b, "// Insertar inserta el registro.")
+		if autoIncremental != nil {
+			fmt.Fprintf(&b, " Asigna en el campo %v el id generado.", autoIncremental.nombre)
+		}
+		fmt.Fprintf(&b, `
+func (repo *%[1]v) Insertar(r *%[2]v) error {
+	sen := repo.ej.
+		Insertar(%[3]q).
+		Tabla(%[4]q).
+		Campos(%[5]v).
+		Valores(%[6]v)
+`, repositorio, estructura, nombreSentencia("insertar"), tabla.Nombre, strings.Join(camposInsertar, ", "), strings.Join(valoresInsertar, ", "))
+		if autoIncremental != nil {
+			fmt.Fprintf(&b, `	var id int64
+	if err := sen.ObtenerID(&id).Ejecutar(); err != nil {
+		return err
+	}
+	r.%v = %v(id)
+
+	return nil
+}
+
+`, autoIncremental.nombre, autoIncremental.tipo)
+		} else {
+			b.WriteString("\n\treturn sen.Ejecutar()\n}\n\n")
+		}
+	}
+
+	if len(clave) > 0 {
+		// modificar: todas las columnas excepto la clave primaria
+		var camposModificar, valoresModificar []string
+		for _, c := range campos {
+			if esDeClave(c, clave) {
+				continue
+			}
+			camposModificar = append(camposModificar, fmt.Sprintf("%q", c.columna.Nombre))
+			valoresModificar = append(valoresModificar, "r."+c.nombre)
+		}
+		if len(camposModificar) > 0 {
+			fmt.Fprintf(&b, `// Modificar modifica el registro identificado por su clave primaria.
+func (repo *%[1]v) Modificar(r *%[2]v) error {
+	return repo.ej.
+		Modificar(%[3]q).
+		Tabla(%[4]q).
+		Campos(%[5]v).
+		Valores(%[6]v).
+		Condicion(%[7]q, %[8]v).
+		Ejecutar()
+}
+
+`, repositorio, estructura, nombreSentencia("modificar"), tabla.Nombre, strings.Join(camposModificar, ", "), strings.Join(valoresModificar, ", "), condicion, strings.Join(valoresClave, ", "))
+		}
+
+		fmt.Fprintf(&b, `// Eliminar elimina el registro identificado por su clave primaria.
+func (repo *%[1]v) Eliminar(%[2]v) error {
+	return repo.ej.
+		Eliminar(%[3]q).
+		Tabla(%[4]q).
+		Condicion(%[5]q, %[6]v).
+		Ejecutar()
+}
+`, repositorio, strings.Join(parametros, ", "), nombreSentencia("eliminar"), tabla.Nombre, condicion, strings.Join(argumentos, ", "))
+	}
+
+	codigo, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("tabla %v: %v", tabla.Nombre, err)
+	}
+
+	return codigo, nil
+}
+
+// camposDeClave devuelve los campos de las columnas de la clave primaria,
+// en el orden de la clave.
+func camposDeClave(campos []campo, clavePrimaria []string) []campo {
+	var clave []campo
+	for _, columna := range clavePrimaria {
+		for _, c := range campos {
+			if strings.EqualFold(c.columna.Nombre, columna) {
+				clave = append(clave, c)
+			}
+		}
+	}
+
+	return clave
+}
+
+// esDeClave informa si el campo forma parte de la clave primaria.
+func esDeClave(c campo, clave []campo) bool {
+	for _, k := range clave {
+		if k.nombre == c.nombre {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tipoGo devuelve el tipo de Go del campo de la columna. Las columnas que
+// admiten valores nulos utilizan los tipos nulos de database/sql.
+func tipoGo(c bdsql.Columna) string {
+	var sinSigno = strings.Contains(c.Tipo, "unsigned")
+	var tipo, tipoNulo string
+	switch c.TipoDeDato {
+	case "tinyint":
+		if strings.HasPrefix(c.Tipo, "tinyint(1)") {
+			tipo, tipoNulo = "bool", "sql.NullBool"
+		} else if sinSigno {
+			tipo, tipoNulo = "uint8", "sql.NullInt32"
+		} else {
+			tipo, tipoNulo = "int8", "sql.NullInt32"
+		}
+	case "smallint", "year":
+		if sinSigno {
+			tipo, tipoNulo = "uint16", "sql.NullInt32"
+		} else {
+			tipo, tipoNulo = "int16", "sql.NullInt32"
+		}
+	case "mediumint", "int":
+		if sinSigno {
+			tipo, tipoNulo = "uint32", "sql.NullInt64"
+		} else {
+			tipo, tipoNulo = "int32", "sql.NullInt32"
+		}
+	case "bigint":
+		if sinSigno {
+			tipo, tipoNulo = "uint64", "sql.NullInt64"
+		} else {
+			tipo, tipoNulo = "int64", "sql.NullInt64"
+		}
+	case "float":
+		tipo, tipoNulo = "float32", "sql.NullFloat64"
+	case "double", "decimal":
+		tipo, tipoNulo = "float64", "sql.NullFloat64"
+	case "date", "datetime", "timestamp":
+		tipo, tipoNulo = "time.Time", "sql.NullTime"
+	default:
+		// textos, json, enum, set, time, binarios, etc.
+		tipo, tipoNulo = "string", "sql.NullString"
+	}
+
+	if c.Nulo {
+		return tipoNulo
+	}
+
+	return tipo
+}
+
+// iniciales contiene las palabras que se escriben en mayúsculas en los
+// nombres de Go.
+var iniciales = map[string]bool{"id": true, "url": true, "uri": true, "json": true, "xml": true, "html": true, "http": true, "ip": true, "sql": true, "api": true, "uuid": true}
+
+// nombreGo convierte un nombre de la base de datos ('persona_id') en un
+// nombre exportado de Go ('PersonaID').
+func nombreGo(nombre string) string {
+	var palabras = strings.FieldsFunc(nombre, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, p := range palabras {
+		if iniciales[strings.ToLower(p)] {
+			b.WriteString(strings.ToUpper(p))
+			continue
+		}
+		var runas = []rune(p)
+		b.WriteString(string(unicode.ToUpper(runas[0])) + string(runas[1:]))
+	}
+
+	var resultado = b.String()
+	if resultado == "" || unicode.IsDigit([]rune(resultado)[0]) {
+		resultado = "C" + resultado
+	}
+
+	return resultado
+}
+
+// nombreParametro convierte el nombre de un campo ('PersonaID') en el
+// nombre de un parámetro ('personaID'), evitando las palabras reservadas.
+func nombreParametro(nombre string) string {
+	var runas = []rune(nombre)
+	var i = 0
+	for i < len(runas) && unicode.IsUpper(runas[i]) {
+		i++
+	}
+	// 'ID' → 'id', 'URLBase' → 'urlBase'
+	if i > 1 && i < len(runas) {
+		i--
+	}
+	var parametro = strings.ToLower(string(runas[:i])) + string(runas[i:])
+	if token.IsKeyword(parametro) {
+		parametro = "valor" + nombre
+	}
+
+	return parametro
+}
+
+// singular convierte el nombre en plural al singular con las reglas del
+// idioma castellano: 'Personas' → 'Persona', 'Ciudades' → 'Ciudad',
+// 'Luces' → 'Luz'. Solo se modifica la última palabra del nombre.
+func singular(nombre string) string {
+	switch {
+	case strings.HasSuffix(nombre, "ces") && len(nombre) > 4:
+		return strings.TrimSuffix(nombre, "ces") + "z"
+	case strings.HasSuffix(nombre, "es") && len(nombre) > 3 && strings.ContainsRune("dlrnsjy", rune(nombre[len(nombre)-3])):
+		return strings.TrimSuffix(nombre, "es")
+	case strings.HasSuffix(nombre, "s") && len(nombre) > 2:
+		return strings.TrimSuffix(nombre, "s")
+	}
+
+	return nombre
+}
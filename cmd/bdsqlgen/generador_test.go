@@ -0,0 +1,130 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+const ddlPrueba = `
+-- personas y sus teléfonos
+create table if not exists personas (
+	id bigint unsigned not null auto_increment,
+	apellidos varchar(100) not null default 'sin; apellido',
+	saldo decimal(10,2) default -1.50,
+	es_activo boolean not null default true,
+	alta datetime not null default current_timestamp(),
+	baja date,
+	primary key (id),
+	unique key uq_apellidos (apellidos)
+) engine = InnoDB;
+
+create table ` + "`telefonos`" + ` (
+	persona_id bigint unsigned not null,
+	numero varchar(30) not null,
+	primary key (persona_id, numero),
+	constraint fk_telefonos_personas foreign key (persona_id) references personas (id) on delete cascade
+);
+`
+
+func TestAnalizarDDL(t *testing.T) {
+	tablas := analizarDDL(ddlPrueba)
+	if len(tablas) != 2 || tablas[0].Nombre != "personas" || tablas[1].Nombre != "telefonos" {
+		t.Fatalf("tablas incorrectas: %+v", tablas)
+	}
+
+	personas := tablas[0]
+	if len(personas.Columnas) != 6 || strings.Join(personas.ClavePrimaria, ",") != "id" {
+		t.Fatalf("tabla incorrecta: %+v", personas)
+	}
+	id, _ := personas.Columna("id")
+	if id.Tipo != "bigint unsigned" || id.Nulo || !id.AutoIncremental {
+		t.Errorf("columna incorrecta: %+v", id)
+	}
+	apellidos, _ := personas.Columna("apellidos")
+	if apellidos.Tipo != "varchar(100)" || apellidos.PorDefecto.String != "sin; apellido" {
+		t.Errorf("columna incorrecta: %+v", apellidos)
+	}
+	saldo, _ := personas.Columna("saldo")
+	if saldo.Tipo != "decimal(10,2)" || !saldo.Nulo || saldo.PorDefecto.String != "-1.50" {
+		t.Errorf("columna incorrecta: %+v", saldo)
+	}
+	if len(personas.Indices) != 1 || personas.Indices[0].Nombre != "uq_apellidos" || !personas.Indices[0].Unico {
+		t.Errorf("índices incorrectos: %+v", personas.Indices)
+	}
+
+	fk := tablas[1].ClavesForaneas
+	if len(fk) != 1 || fk[0].Nombre != "fk_telefonos_personas" || fk[0].TablaReferencia != "personas" || fk[0].AlEliminar != "CASCADE" {
+		t.Errorf("claves foráneas incorrectas: %+v", fk)
+	}
+}
+
+func TestGenerar(t *testing.T) {
+	tablas := analizarDDL(ddlPrueba)
+	g := generador{paquete: "modelos", singular: true}
+
+	codigo, err := g.generar(tablas[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, esperado := range []string{
+		"type Persona struct {",
+		"ID        uint64          `bdsql:\"id\"`",
+		"Saldo     sql.NullFloat64 `bdsql:\"saldo\"`",
+		"EsActivo  bool            `bdsql:\"es_activo\"`",
+		"Baja      sql.NullTime    `bdsql:\"baja\"`",
+		"func (repo *RepositorioPersona) Obtener(id uint64) (Persona, bool, error) {",
+		`Insertar("bdsqlgen.personas.insertar").`,
+		"r.ID = uint64(id)",
+		"func (repo *RepositorioPersona) Modificar(r *Persona) error {",
+	} {
+		if !strings.Contains(string(codigo), esperado) {
+			t.Errorf("no se encuentra %q en el código generado:\n%s", esperado, codigo)
+		}
+	}
+
+	// clave primaria compuesta
+	codigo, err = g.generar(tablas[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if esperado := `func (repo *RepositorioTelefono) Eliminar(personaID uint64, numero string) error {`; !strings.Contains(string(codigo), esperado) {
+		t.Errorf("no se encuentra %q en el código generado:\n%s", esperado, codigo)
+	}
+	if strings.Contains(string(codigo), "Modificar(") {
+		t.Errorf("no se esperaba el método Modificar (todas las columnas son de la clave):\n%s", codigo)
+	}
+}
+
+// TestGenerarCompila verifica que el código generado de todas las tablas,
+// reunido en un mismo paquete, sea código de Go válido (análisis de tipos
+// contra el paquete bdsql).
+func TestGenerarCompila(t *testing.T) {
+	tablas := analizarDDL(ddlPrueba)
+	fset := token.NewFileSet()
+
+	var archivos []*ast.File
+	for _, g := range []generador{{paquete: "modelos", singular: true}, {paquete: "modelos"}} {
+		for _, tabla := range tablas {
+			codigo, err := g.generar(tabla)
+			if err != nil {
+				t.Fatal(err)
+			}
+			archivo, err := parser.ParseFile(fset, tabla.Nombre+".go", codigo, 0)
+			if err != nil {
+				t.Fatalf("%v\n%s", err, codigo)
+			}
+			archivos = append(archivos, archivo)
+		}
+
+		conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+		if _, err := conf.Check("modelos", fset, archivos, nil); err != nil {
+			t.Errorf("el código generado no compila: %v", err)
+		}
+		archivos = nil
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Bdsqlgen genera las estructuras con etiquetas "bdsql" y los repositorios
+de las tablas de una base de datos, a partir del esquema de una base de datos
+en funcionamiento o de los archivos de sentencias 'create table' (sin
+conexión).
+
+Uso:
+
+	bdsqlgen -dsn 'usuario:clave@tcp(localhost:3306)/base' -paquete modelos -salida ./modelos
+	bdsqlgen -ddl ./esquema -paquete modelos -salida ./modelos
+
+Por cada tabla se genera el archivo '<tabla>.go' con la estructura del
+registro (las columnas que admiten valores nulos utilizan los tipos nulos de
+database/sql) y un repositorio con los métodos Obtener, Listar, Insertar,
+Modificar y Eliminar, construidos con las sentencias de bdsql y nombres de
+sentencia únicos ('bdsqlgen.<tabla>.<operación>'). Las tablas sin clave
+primaria solo obtienen los métodos Listar e Insertar.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fabianpallares/bdsql"
+)
+
+func main() {
+	var (
+		dsn      = flag.String("dsn", "", "cadena de conexión de la base de datos (Mysql/MariaDB)")
+		ddl      = flag.String("ddl", "", "directorio con los archivos '.sql' de las sentencias 'create table'")
+		paquete  = flag.String("paquete", "modelos", "nombre del paquete generado")
+		salida   = flag.String("salida", ".", "directorio de los archivos generados")
+		tablas   = flag.String("tablas", "", "tablas a generar, separadas por comas (por defecto, todas)")
+		singular = flag.Bool("singular", true, "nombrar las estructuras en singular ('personas' → Persona)")
+	)
+	flag.Parse()
+
+	if err := ejecutar(*dsn, *ddl, *paquete, *salida, *tablas, *singular); err != nil {
+		fmt.Fprintln(os.Stderr, "bdsqlgen:", err)
+		os.Exit(1)
+	}
+}
+
+func ejecutar(dsn, ddl, paquete, salida, filtro string, singular bool) error {
+	if (dsn == "") == (ddl == "") {
+		return fmt.Errorf("debe indicarse una cadena de conexión (-dsn) o un directorio de sentencias (-ddl)")
+	}
+
+	var tablas []bdsql.Tabla
+	if dsn != "" {
+		bd, err := bdsql.Conectar(dsn, 1, 1)
+		if err != nil {
+			return err
+		}
+		defer bd.Cerrar()
+
+		esquema, err := bd.Esquema()
+		if err != nil {
+			return err
+		}
+		tablas = esquema.Tablas
+	} else {
+		var err error
+		if tablas, err = leerDDL(ddl); err != nil {
+			return err
+		}
+	}
+
+	var incluir = make(map[string]bool)
+	for _, t := range strings.Split(filtro, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			incluir[strings.ToLower(t)] = true
+		}
+	}
+
+	if err := os.MkdirAll(salida, 0755); err != nil {
+		return err
+	}
+	var g = generador{paquete: paquete, singular: singular}
+	for _, t := range tablas {
+		if len(incluir) > 0 && !incluir[strings.ToLower(t.Nombre)] {
+			continue
+		}
+		codigo, err := g.generar(t)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(salida, strings.ToLower(t.Nombre)+".go"), codigo, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package bdsql
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// Dialecto representa la variante del motor de base de datos para la cual se
+// generan las sentencias SQL.
+type Dialecto int
+
+const (
+	// Mysql representa al motor Mysql (versión 8 o superior).
+	Mysql Dialecto = iota
+	// Mariadb representa al motor MariaDB (versión 10.6 o superior).
+	Mariadb
+)
+
+// AsignarDialecto establece la variante del motor de base de datos con la
+// cual se generan las sentencias SQL. Por defecto se utiliza Mysql.
+// Debe asignarse antes de generar cualquier sentencia, dado que las
+// sentencias generadas se almacenan por su nombre.
+func (bd *BD) AsignarDialecto(dialecto Dialecto) *BD {
+	bd.mux.Lock()
+	bd.dialecto = dialecto
+	bd.mux.Unlock()
+
+	return bd
+}
+
+// obtenerDialecto devuelve la variante del motor de base de datos.
+func (bd *BD) obtenerDialecto() Dialecto {
+	bd.mux.Lock()
+	defer bd.mux.Unlock()
+
+	return bd.dialecto
+}
+
+// clausulaCompartir devuelve la cláusula de bloqueo compartido de registros.
+func (d Dialecto) clausulaCompartir() string {
+	if d == Mariadb {
+		return "lock in share mode"
+	}
+
+	return "for share"
+}
+
+// admiteSugerencias informa si el dialecto admite las sugerencias al
+// optimizador ('/*+ ... */').
+func (d Dialecto) admiteSugerencias() bool {
+	return d == Mysql
+}
+
+// admiteSiExisteEnModificaciones informa si el dialecto admite las cláusulas
+// 'if exists' e 'if not exists' al agregar o eliminar columnas e índices
+// ('alter table', 'create index' y 'drop index').
+func (d Dialecto) admiteSiExisteEnModificaciones() bool {
+	return d == Mariadb
+}
+
+// normalizarPorDefecto unifica el valor por defecto de una columna obtenido
+// de 'information_schema.columns'. Mariadb devuelve los textos entre
+// comillas y el texto 'NULL' cuando el valor por defecto es nulo; Mysql
+// devuelve el valor sin comillas y un valor nulo.
+func (d Dialecto) normalizarPorDefecto(valor sql.NullString) sql.NullString {
+	if d != Mariadb || !valor.Valid {
+		return valor
+	}
+	if valor.String == "NULL" {
+		return sql.NullString{}
+	}
+	if n := len(valor.String); n >= 2 && valor.String[0] == '\'' && valor.String[n-1] == '\'' {
+		valor.String = strings.ReplaceAll(valor.String[1:n-1], "''", "'")
+	}
+
+	return valor
+}
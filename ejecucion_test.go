@@ -0,0 +1,392 @@
+package bdsql_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/fabianpallares/bdsql"
+	"github.com/fabianpallares/bdsql/bdsqltest"
+)
+
+func TestInsertarEnSentenciaPreparada(t *testing.T) {
+	bd, ctrl := bdsqltest.Nuevo(t)
+	for i := 0; i < 3; i++ {
+		ctrl.Esperar("insert into cosas (nombre, datos, es_activo, observaciones) values (?, ?, ?, ?);").
+			ConValores(fmt.Sprintf("nombre-%v", i), "{}", true, fmt.Sprintf("observaciones-%v", i)).
+			DevolverResultado(int64(i+1), 1)
+	}
+
+	sp, err := bd.
+		Insertar("").
+		Tabla("cosas").
+		Campos("nombre", "datos", "es_activo", "observaciones").
+		SentenciaPreparada()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sp.Cerrar()
+
+	var id int64
+	for i := 0; i < 3; i++ {
+		if err := sp.Valores(fmt.Sprintf("nombre-%v", i), "{}", true, fmt.Sprintf("observaciones-%v", i)).ObtenerID(&id).Ejecutar(); err != nil {
+			t.Fatal(err)
+		}
+		if id != int64(i+1) {
+			t.Errorf("id incorrecto: obtenido %v, esperado %v", id, i+1)
+		}
+	}
+}
+
+func TestConflictoDeVersion(t *testing.T) {
+	bd, ctrl := bdsqltest.Nuevo(t)
+	ctrl.Esperar("update cosas set nombre = ?, version = version + 1 where (id in (?, ?)) and version = ?;").
+		ConValores("uno", 1, 2, 3)
+	ctrl.Esperar("select count(*) from cosas where id in (?, ?);").
+		ConValores(1, 2).
+		DevolverFilas(bdsqltest.NuevasFilas("count(*)").Agregar(2))
+	ctrl.Esperar("update otras set nombre = ?, version = version + 1 where (id = ?) and version = ?;").
+		ConValores("dos", 5, 1)
+	ctrl.Esperar("select count(*) from otras where id = ?;").
+		ConValores(5).
+		DevolverFilas(bdsqltest.NuevasFilas("count(*)").Agregar(0))
+
+	// la condición se expande también en la verificación de la existencia
+	err := bd.Modificar("-").Tabla("cosas").Campos("nombre").Valores("uno").
+		Condicion("id in (?)", []int{1, 2}).ConVersion("version", 3).Ejecutar()
+	if e, ok := bdsql.EsError(err); !ok || !e.EsConflictoDeVersion() {
+		t.Errorf("se esperaba el error de conflicto de versión: %v", err)
+	}
+
+	// las sentencias sin nombre no comparten la verificación de la existencia
+	err = bd.Modificar("-").Tabla("otras").Campos("nombre").Valores("dos").
+		Condicion(bdsql.Igual("id", 5)).ConVersion("version", 1).Ejecutar()
+	if e, ok := bdsql.EsError(err); !ok || !e.EsNingunRegistroAfectado() || e.EsConflictoDeVersion() {
+		t.Errorf("se esperaba el error de ningún registro afectado: %v", err)
+	}
+}
+
+func TestConVersionConNombre(t *testing.T) {
+	bd, ctrl := bdsqltest.Nuevo(t)
+	for i := 0; i < 2; i++ {
+		ctrl.Esperar("update cosas set nombre = ?, version = version + 1 where (id = ?) and version = ?;").
+			ConValores("uno", i, 3)
+		ctrl.Esperar("select count(*) from cosas where id = ?;").
+			ConValores(i).
+			DevolverFilas(bdsqltest.NuevasFilas("count(*)").Agregar(1))
+	}
+	ctrl.Esperar("update otras set nombre = ? where id = ?;").
+		ConValores("dos", 1).
+		DevolverResultado(0, 1)
+
+	// la segunda ejecución utiliza las sentencias almacenadas con el nombre,
+	// incluida la verificación de la existencia del registro
+	for i := 0; i < 2; i++ {
+		err := bd.Modificar("cosasModificarConVersion").Tabla("cosas").Campos("nombre").Valores("uno").
+			Condicion("id = ?", i).ConVersion("version", 3).Ejecutar()
+		if e, ok := bdsql.EsError(err); !ok || !e.EsConflictoDeVersion() {
+			t.Errorf("ejecución %v: se esperaba el error de conflicto de versión: %v", i, err)
+		}
+	}
+
+	// el mismo nombre con un uso distinto de ConVersion no se ejecuta
+	err := bd.Modificar("cosasModificarConVersion").Tabla("cosas").Campos("nombre").Valores("uno").
+		Condicion("id = ?", 1).Ejecutar()
+	if e, ok := bdsql.EsError(err); !ok || !e.EsVersionIncompatible() {
+		t.Errorf("se esperaba el error de versión incompatible: %v", err)
+	}
+	if err := bd.Modificar("otrasModificar").Tabla("otras").Campos("nombre").Valores("dos").
+		Condicion("id = ?", 1).Ejecutar(); err != nil {
+		t.Fatal(err)
+	}
+	err = bd.Modificar("otrasModificar").Tabla("otras").Campos("nombre").Valores("dos").
+		Condicion("id = ?", 1).ConVersion("version", 3).Ejecutar()
+	if e, ok := bdsql.EsError(err); !ok || !e.EsVersionIncompatible() {
+		t.Errorf("se esperaba el error de versión incompatible: %v", err)
+	}
+}
+
+func TestConflictoDeVersionEnSentenciaPreparada(t *testing.T) {
+	bd, ctrl := bdsqltest.Nuevo(t)
+	ctrl.Esperar("update cosas set nombre = ?, version = version + 1 where (id = ?) and version = ?;").
+		ConValores("uno", 1, 3).
+		DevolverResultado(0, 1)
+	ctrl.Esperar("update cosas set nombre = ?, version = version + 1 where (id = ?) and version = ?;").
+		ConValores("uno", 1, 3)
+	ctrl.Esperar("select count(*) from cosas where id = ?;").
+		ConValores(1).
+		DevolverFilas(bdsqltest.NuevasFilas("count(*)").Agregar(1))
+
+	sp, err := bd.Modificar("cosasModificarConVersion").Tabla("cosas").Campos("nombre").
+		Condicion("id = ?").ConVersion("version", nil).SentenciaPreparada()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sp.Cerrar()
+
+	if err = sp.Valores("uno", 1, 3).Ejecutar(); err != nil {
+		t.Fatal(err)
+	}
+	err = sp.Valores("uno", 1, 3).Ejecutar()
+	if e, ok := bdsql.EsError(err); !ok || !e.EsConflictoDeVersion() {
+		t.Errorf("se esperaba el error de conflicto de versión: %v", err)
+	}
+}
+
+func TestEjecutarResultado(t *testing.T) {
+	bd, ctrl := bdsqltest.Nuevo(t)
+	ctrl.Esperar("update cosas set nombre = ? where id = ?;").
+		ConValores("uno", 1).
+		DevolverResultado(0, 0)
+	ctrl.Esperar("show warnings;").
+		DevolverFilas(bdsqltest.NuevasFilas("Level", "Code", "Message").Agregar("Warning", 1265, "Data truncated for column 'nombre' at row 1"))
+	ctrl.Esperar("update cosas set nombre = ? where id = ?;").
+		ConValores("uno", 1).
+		DevolverResultado(0, 0)
+	ctrl.Esperar("show warnings;").
+		DevolverFilas(bdsqltest.NuevasFilas("Level", "Code", "Message"))
+	ctrl.Esperar("insert into cosas (nombre) values (?);").
+		ConValores("dos").
+		DevolverResultado(7, 1)
+	ctrl.Esperar("show warnings;")
+
+	// ningún registro afectado: el resultado se devuelve junto con el error
+	res, err := bd.Modificar("-").Tabla("cosas").Campos("nombre").Valores("uno").Condicion("id = ?", 1).EjecutarResultado()
+	if e, ok := bdsql.EsError(err); !ok || !e.EsNingunRegistroAfectado() {
+		t.Errorf("se esperaba el error de ningún registro afectado: %v", err)
+	}
+	if res.RegistrosAfectados != 0 || len(res.Advertencias) != 1 || res.Advertencias[0] != "Warning (1265): Data truncated for column 'nombre' at row 1" {
+		t.Errorf("resultado incorrecto: %+v", res)
+	}
+
+	res, err = bd.Modificar("-").Tabla("cosas").Campos("nombre").Valores("uno").Condicion("id = ?", 1).PermitirCeroAfectados().EjecutarResultado()
+	if err != nil || res.RegistrosAfectados != 0 || len(res.Advertencias) != 0 {
+		t.Errorf("resultado incorrecto: %+v %v", res, err)
+	}
+
+	res, err = bd.Insertar("-").Tabla("cosas").Campos("nombre").Valores("dos").EjecutarResultado()
+	if err != nil || res.RegistrosAfectados != 1 || res.UltimoID != 7 {
+		t.Errorf("resultado incorrecto: %+v %v", res, err)
+	}
+}
+
+func TestBloqueoDeRegistros(t *testing.T) {
+	bd, ctrl := bdsqltest.Nuevo(t)
+
+	_, err := bd.Seleccionar("-").Tabla("stock").Campos("*").ParaModificar().SQL()
+	if e, ok := bdsql.EsError(err); !ok || !e.EsBloqueoFueraDeTransaccion() {
+		t.Errorf("se esperaba el error de bloqueo fuera de una transacción: %v", err)
+	}
+
+	tx, err := bd.TxIniciar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.TxRevertir()
+
+	sentencia, err := tx.Seleccionar("-").Tabla("stock").Campos("*").Condicion("id = ?", 1).ParaModificar().SaltarBloqueados().SQL()
+	if esperada := "select * from stock where id = ? for update skip locked;"; err != nil || sentencia != esperada {
+		t.Errorf("sentencia incorrecta:\n obtenida: %v %v\n esperada: %v", sentencia, err, esperada)
+	}
+
+	// Mysql informa los registros bloqueados con el error 3572
+	var registros []struct {
+		ID int64 `bdsql:"id"`
+	}
+	ctrl.Esperar("select id from stock where id = ? for update nowait;").
+		DevolverError(bdsqltest.ErrorMysql(3572, "Statement aborted because lock(s) could not be acquired immediately and NOWAIT is set."))
+	_, err = tx.Seleccionar("-").Tabla("stock").Campos("id").Condicion("id = ?", 1).ParaModificar().SinEspera().Resultado(&registros).Ejecutar()
+	if e, ok := bdsql.EsError(err); !ok || !e.EsBloqueoNoDisponible() {
+		t.Errorf("se esperaba el error de bloqueo no disponible: %v", err)
+	}
+
+	// Mariadb informa los registros bloqueados con el error 1205
+	bd.AsignarDialecto(bdsql.Mariadb)
+	ctrl.Esperar("select id from stock lock in share mode nowait;").
+		DevolverError(bdsqltest.ErrorMysql(1205, "Lock wait timeout exceeded; try restarting transaction"))
+	_, err = tx.Seleccionar("-").Tabla("stock").Campos("id").ParaCompartir().SinEspera().Resultado(&registros).Ejecutar()
+	if e, ok := bdsql.EsError(err); !ok || !e.EsBloqueoNoDisponible() {
+		t.Errorf("se esperaba el error de bloqueo no disponible: %v", err)
+	}
+
+	// sin 'nowait' ni 'skip locked', el error 1205 es el vencimiento de la
+	// espera del bloqueo y no un bloqueo no disponible
+	ctrl.Esperar("select id from stock;").
+		DevolverError(bdsqltest.ErrorMysql(1205, "Lock wait timeout exceeded; try restarting transaction"))
+	ctrl.Esperar("select id from stock for update;").
+		DevolverError(bdsqltest.ErrorMysql(1205, "Lock wait timeout exceeded; try restarting transaction"))
+	for _, sel := range []func() (int, error){
+		tx.Seleccionar("-").Tabla("stock").Campos("id").Resultado(&registros).Ejecutar,
+		tx.Seleccionar("-").Tabla("stock").Campos("id").ParaModificar().Resultado(&registros).Ejecutar,
+	} {
+		_, err = sel()
+		if e, ok := bdsql.EsError(err); !ok || e.EsBloqueoNoDisponible() {
+			t.Errorf("no se esperaba el error de bloqueo no disponible: %v", err)
+		}
+	}
+}
+
+// conexionContada es una conexión propia que envuelve el pool de conexiones
+// y cuenta las sentencias de escritura ejecutadas y las preparadas.
+type conexionContada struct {
+	bdsql.Conexion
+	ejecutadas int
+	preparadas int
+}
+
+func (c *conexionContada) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	c.ejecutadas++
+	return c.Conexion.ExecContext(ctx, query, args...)
+}
+
+func (c *conexionContada) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	c.preparadas++
+	return c.Conexion.PrepareContext(ctx, query)
+}
+
+func TestSesion(t *testing.T) {
+	bd, ctrl := bdsqltest.Nuevo(t)
+	ctrl.Esperar("insert into cosas (nombre) values (?);").
+		ConValores("uno").
+		DevolverResultado(3, 1)
+
+	// las sentencias se ejecutan sobre la conexión recibida
+	con := &conexionContada{Conexion: bd.DB()}
+	var ej bdsql.Ejecutor = bd.Sesion(con)
+	var id int64
+	if err := ej.Insertar("-").Tabla("cosas").Campos("nombre").Valores("uno").ObtenerID(&id).Ejecutar(); err != nil {
+		t.Fatal(err)
+	}
+	if id != 3 || con.ejecutadas != 1 {
+		t.Errorf("ejecución incorrecta: id %v, sentencias ejecutadas %v", id, con.ejecutadas)
+	}
+
+	// en el modo simulación las sentencias nativas no se ejecutan
+	var salida strings.Builder
+	bd.ModoSimulacion(true).SalidaSimulacion(&salida)
+	res, err := ej.ExecContext(context.Background(), "delete from cosas where id = ?;", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if afectados, _ := res.RowsAffected(); afectados != 1 || con.ejecutadas != 1 {
+		t.Errorf("resultado simulado incorrecto: %v afectados, sentencias ejecutadas %v", afectados, con.ejecutadas)
+	}
+	if _, err = bd.ExecContext(context.Background(), "delete from otras;"); err != nil {
+		t.Fatal(err)
+	}
+	if esperada := "delete from cosas where id = 3;\ndelete from otras;\n"; salida.String() != esperada {
+		t.Errorf("salida de la simulación incorrecta:\n obtenida: %q\n esperada: %q", salida.String(), esperada)
+	}
+}
+
+func TestSentenciaPreparadaEnModoSimulacion(t *testing.T) {
+	bd, _ := bdsqltest.Nuevo(t)
+	var salida strings.Builder
+	bd.ModoSimulacion(true).SalidaSimulacion(&salida)
+
+	// la sentencia no se prepara en el servidor ni se ejecuta
+	con := &conexionContada{Conexion: bd.DB()}
+	sp, err := bd.Sesion(con).Insertar("-").Tabla("cosas").Campos("nombre").SentenciaPreparada()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sp.Valores("uno").Ejecutar(); err != nil {
+		t.Fatal(err)
+	}
+	if err := sp.Cerrar(); err != nil {
+		t.Fatal(err)
+	}
+	if con.preparadas != 0 || con.ejecutadas != 0 {
+		t.Errorf("sentencias preparadas %v, ejecutadas %v", con.preparadas, con.ejecutadas)
+	}
+	if esperada := "insert into cosas (nombre) values ('uno');\n"; salida.String() != esperada {
+		t.Errorf("salida de la simulación incorrecta:\n obtenida: %q\n esperada: %q", salida.String(), esperada)
+	}
+}
+
+// mayusculas implementa sql.Scanner: asigna el texto en mayúsculas.
+type mayusculas string
+
+func (m *mayusculas) Scan(valor interface{}) error {
+	switch v := valor.(type) {
+	case nil:
+		*m = ""
+	case []byte:
+		*m = mayusculas(strings.ToUpper(string(v)))
+	case string:
+		*m = mayusculas(strings.ToUpper(v))
+	default:
+		return fmt.Errorf("tipo no admitido: %T", valor)
+	}
+
+	return nil
+}
+
+func TestResultadoConScanner(t *testing.T) {
+	bd, ctrl := bdsqltest.Nuevo(t)
+	ctrl.Esperar("select nombre, apodo, codigo from personas;").
+		DevolverFilas(bdsqltest.NuevasFilas("nombre", "apodo", "codigo").
+			Agregar("Ana", nil, []byte("ab")).
+			Agregar("Luis", "Lu", nil))
+	ctrl.Esperar("select codigo from personas;").
+		DevolverFilas(bdsqltest.NuevasFilas("codigo").Agregar(10))
+
+	// los tipos nulos de database/sql y los tipos propios que implementan
+	// sql.Scanner asignan su propio valor
+	var personas []struct {
+		Nombre string         `bdsql:"nombre"`
+		Apodo  sql.NullString `bdsql:"apodo"`
+		Codigo mayusculas     `bdsql:"codigo"`
+	}
+	n, err := bd.Seleccionar("-").Tabla("personas").Campos("nombre", "apodo", "codigo").Resultado(&personas).Ejecutar()
+	if err != nil || n != 2 {
+		t.Fatalf("registros incorrectos: %v %v", n, err)
+	}
+	if personas[0].Apodo.Valid || personas[0].Codigo != "AB" || !personas[1].Apodo.Valid || personas[1].Apodo.String != "Lu" || personas[1].Codigo != "" {
+		t.Errorf("registros incorrectos: %+v", personas)
+	}
+
+	// el error del Scanner se informa como error de asignación
+	var codigos []struct {
+		Codigo mayusculas `bdsql:"codigo"`
+	}
+	_, err = bd.Seleccionar("-").Tabla("personas").Campos("codigo").Resultado(&codigos).Ejecutar()
+	if e, ok := bdsql.EsError(err); !ok || !e.EsSeleccionarAsignacionDeCampos() {
+		t.Errorf("se esperaba el error de asignación de campos: %v", err)
+	}
+}
+
+func TestSumarYPromediar(t *testing.T) {
+	bd, ctrl := bdsqltest.Nuevo(t)
+	// mysql devuelve la suma de una columna 'decimal' como texto
+	ctrl.Esperar("select sum(importe) from ventas where zona = ?;").
+		ConValores("centro").
+		DevolverFilas(bdsqltest.NuevasFilas("sum(importe)").Agregar([]byte("12345678901234567.89")))
+	ctrl.Esperar("select avg(importe) from ventas;").
+		DevolverFilas(bdsqltest.NuevasFilas("avg(importe)").Agregar([]byte("2.5")))
+	ctrl.Esperar("select sum(importe) from ventas where zona = ?;").
+		ConValores("norte").
+		DevolverFilas(bdsqltest.NuevasFilas("sum(importe)").Agregar(nil))
+
+	// el destino de texto conserva la precisión del decimal
+	var total string
+	existe, err := bd.Seleccionar("-").Tabla("ventas").Condicion("zona = ?", "centro").Sumar("importe", &total)
+	if err != nil || !existe || total != "12345678901234567.89" {
+		t.Errorf("suma incorrecta: %v %v %v", total, existe, err)
+	}
+
+	var promedio float64
+	existe, err = bd.Seleccionar("-").Tabla("ventas").Promediar("importe", &promedio)
+	if err != nil || !existe || promedio != 2.5 {
+		t.Errorf("promedio incorrecto: %v %v %v", promedio, existe, err)
+	}
+
+	// sin registros el destino no se modifica
+	total = "-"
+	existe, err = bd.Seleccionar("-").Tabla("ventas").Condicion("zona = ?", "norte").Sumar("importe", &total)
+	if err != nil || existe || total != "-" {
+		t.Errorf("suma incorrecta: %v %v %v", total, existe, err)
+	}
+}
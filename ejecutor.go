@@ -0,0 +1,192 @@
+package bdsql
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Ejecutor representa a quien crea y ejecuta las sentencias: una base de
+// datos (*BD) o una transacción (*TX). Las funciones que reciben un
+// Ejecutor funcionan de la misma manera dentro o fuera de una transacción:
+//
+//	func descontarStock(ej bdsql.Ejecutor, id int64, cantidad int) error {
+//		return ej.Modificar("productosDescontarStock").
+//			Tabla("productos").
+//			Expresion("stock", "stock - ?", cantidad).
+//			Condicion("id = ?", id).
+//			Ejecutar()
+//	}
+//
+//	err := descontarStock(bd, 1, 3)
+//	err = descontarStock(tx, 1, 3)
+//
+// Lo implementan BD, TX y Sesion. Para ejecutar las sentencias sobre otra
+// conexión (una conexión reservada, una réplica o un tipo propio que
+// envuelve la conexión) se implementa Conexion y se utiliza BD.Sesion.
+type Ejecutor interface {
+	Insertar(nombre string) *insertar
+	Modificar(nombre string) *modificar
+	Eliminar(nombre string) *eliminar
+	Seleccionar(nombre string) *seleccionar
+	Restaurar(nombre string) *restaurar
+
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// verificación de que BD, TX y Sesion implementan Ejecutor
+var (
+	_ Ejecutor = (*BD)(nil)
+	_ Ejecutor = (*TX)(nil)
+	_ Ejecutor = (*Sesion)(nil)
+)
+
+// Conexion representa la conexión sobre la cual se ejecutan las sentencias:
+// el pool de conexiones (*sql.DB), una transacción (*sql.Tx), una conexión
+// reservada (*sql.Conn) o cualquier tipo que implemente sus métodos.
+type Conexion interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// enTransaccion informa si la conexión es una transacción.
+func enTransaccion(con Conexion) bool {
+	_, ok := con.(*sql.Tx)
+	return ok
+}
+
+// ExecContext ejecuta una sentencia SQL nativa en el pool de conexiones. En
+// el modo simulación la sentencia no se ejecuta.
+func (bd *BD) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return ejecutarNativa(ctx, bd, bd.db, query, args)
+}
+
+// QueryContext ejecuta una consulta SQL nativa en el pool de conexiones.
+func (bd *BD) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return bd.db.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext ejecuta una consulta SQL nativa que obtiene un único
+// registro en el pool de conexiones.
+func (bd *BD) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return bd.db.QueryRowContext(ctx, query, args...)
+}
+
+// PrepareContext crea una sentencia preparada SQL nativa en el pool de
+// conexiones.
+func (bd *BD) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return bd.db.PrepareContext(ctx, query)
+}
+
+// ExecContext ejecuta una sentencia SQL nativa dentro de la transacción. En
+// el modo simulación la sentencia no se ejecuta.
+func (tx *TX) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return ejecutarNativa(ctx, tx.bd, tx.tx, query, args)
+}
+
+// QueryContext ejecuta una consulta SQL nativa dentro de la transacción.
+func (tx *TX) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.tx.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext ejecuta una consulta SQL nativa que obtiene un único
+// registro dentro de la transacción.
+func (tx *TX) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return tx.tx.QueryRowContext(ctx, query, args...)
+}
+
+// PrepareContext crea una sentencia preparada SQL nativa dentro de la
+// transacción.
+func (tx *TX) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return tx.tx.PrepareContext(ctx, query)
+}
+
+// -----------------------------------------------------------------------------
+
+// Sesion crea y ejecuta las sentencias de la base de datos sobre una
+// conexión recibida (ver BD.Sesion).
+type Sesion struct {
+	bd  *BD
+	con Conexion
+}
+
+// Sesion devuelve un Ejecutor que crea y ejecuta las sentencias sobre la
+// conexión recibida. Las sentencias utilizan el dialecto, las sentencias
+// almacenadas y el modo simulación de la base de datos. Los bloqueos de
+// registros ('for update', 'lock in share mode') requieren que la conexión
+// sea una transacción (*sql.Tx).
+//
+//	con, err := bd.DB().Conn(ctx)
+//	...
+//	defer con.Close()
+//	err = descontarStock(bd.Sesion(con), 1, 3)
+func (bd *BD) Sesion(con Conexion) *Sesion {
+	return &Sesion{bd: bd, con: con}
+}
+
+// Insertar representa la sentencia 'insert' de SQL.
+func (s *Sesion) Insertar(nombre string) *insertar {
+	return nuevoInsertar(s.bd, s.con, nombre)
+}
+
+// Modificar representa la sentencia 'update' de SQL.
+func (s *Sesion) Modificar(nombre string) *modificar {
+	return nuevoModificar(s.bd, s.con, nombre)
+}
+
+// Eliminar representa la sentencia 'delete' de SQL.
+func (s *Sesion) Eliminar(nombre string) *eliminar {
+	return nuevoEliminar(s.bd, s.con, nombre)
+}
+
+// Seleccionar representa la sentencia 'select' de SQL.
+func (s *Sesion) Seleccionar(nombre string) *seleccionar {
+	return nuevoSeleccionar(s.bd, s.con, nombre)
+}
+
+// Restaurar representa la sentencia 'update' de SQL que restaura los
+// registros eliminados de una tabla con borrado lógico.
+func (s *Sesion) Restaurar(nombre string) *restaurar {
+	return nuevoRestaurar(s.bd, s.con, nombre)
+}
+
+// ExecContext ejecuta una sentencia SQL nativa sobre la conexión. En el modo
+// simulación la sentencia no se ejecuta.
+func (s *Sesion) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return ejecutarNativa(ctx, s.bd, s.con, query, args)
+}
+
+// QueryContext ejecuta una consulta SQL nativa sobre la conexión.
+func (s *Sesion) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return s.con.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext ejecuta una consulta SQL nativa que obtiene un único
+// registro sobre la conexión.
+func (s *Sesion) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return s.con.QueryRowContext(ctx, query, args...)
+}
+
+// PrepareContext crea una sentencia preparada SQL nativa sobre la conexión.
+func (s *Sesion) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return s.con.PrepareContext(ctx, query)
+}
+
+// ejecutarNativa ejecuta una sentencia SQL nativa sobre la conexión. En el
+// modo simulación la sentencia se escribe en la salida de la simulación y no
+// se ejecuta.
+func ejecutarNativa(ctx context.Context, bd *BD, con Conexion, query string, args []interface{}) (sql.Result, error) {
+	if bd.EnSimulacion() {
+		res, err := bd.simularSentencia(query, args)
+		if err != nil {
+			return nil, err
+		}
+		return resultadoSimulado(res), nil
+	}
+
+	return con.ExecContext(ctx, query, args...)
+}
@@ -0,0 +1,322 @@
+package bdsql
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Esquema representa la estructura de la base de datos de la conexión,
+// obtenida de 'information_schema'.
+type Esquema struct {
+	Tablas []Tabla // tablas ordenadas por nombre
+}
+
+// Tabla representa la estructura de una tabla de la base de datos.
+type Tabla struct {
+	Nombre         string
+	Columnas       []Columna      // columnas en el orden de la tabla
+	ClavePrimaria  []string       // columnas de la clave primaria, en orden
+	Indices        []Indice       // índices, excepto la clave primaria
+	ClavesForaneas []ClaveForanea // claves foráneas
+}
+
+// Columna representa la estructura de una columna de una tabla.
+type Columna struct {
+	Nombre          string
+	Tipo            string         // tipo completo de la columna: "varchar(50)", "int unsigned", etc.
+	TipoDeDato      string         // tipo de dato sin atributos: "varchar", "int", etc.
+	Nulo            bool           // la columna admite valores nulos
+	PorDefecto      sql.NullString // valor por defecto (no válido cuando no tiene o es nulo)
+	AutoIncremental bool           // la columna es 'auto_increment'
+}
+
+// Indice representa un índice de una tabla.
+type Indice struct {
+	Nombre   string
+	Columnas []string // columnas del índice, en orden
+	Unico    bool
+}
+
+// ClaveForanea representa una clave foránea de una tabla.
+type ClaveForanea struct {
+	Nombre             string
+	Columnas           []string
+	TablaReferencia    string
+	ColumnasReferencia []string
+	AlEliminar         string // acción al eliminar el registro referenciado ('CASCADE', 'RESTRICT', etc.)
+	AlModificar        string // acción al modificar el registro referenciado
+}
+
+// Tabla devuelve la tabla con el nombre recibido y un valor lógico que
+// confirma su existencia.
+func (e *Esquema) Tabla(nombre string) (*Tabla, bool) {
+	for i := range e.Tablas {
+		if strings.EqualFold(e.Tablas[i].Nombre, nombre) {
+			return &e.Tablas[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// Columna devuelve la columna con el nombre recibido y un valor lógico que
+// confirma su existencia.
+func (t *Tabla) Columna(nombre string) (*Columna, bool) {
+	for i := range t.Columnas {
+		if strings.EqualFold(t.Columnas[i].Nombre, nombre) {
+			return &t.Columnas[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// Esquema obtiene la estructura de las tablas de la base de datos de la
+// conexión: columnas, claves primarias, índices y claves foráneas.
+func (bd *BD) Esquema() (*Esquema, error) {
+	return bd.leerEsquema("")
+}
+
+// VerificarEstructura verifica que la tabla exista y que contenga las
+// columnas de los campos de la estructura recibida (o puntero a
+// estructura), con un tipo de dato compatible. Los campos se relacionan con
+// las columnas con las mismas reglas que al obtener los registros de un
+// 'select' (etiqueta "bdsql" o nombre del campo en minúsculas).
+// Las diferencias se informan con el error EsEstructuraIncorrecta():
+//
+//	if err := bd.VerificarEstructura("personas", &Persona{}); err != nil {
+//		log.Fatal(err)
+//	}
+func (bd *BD) VerificarEstructura(tabla string, estructura interface{}) error {
+	var tipo = reflect.TypeOf(estructura)
+	if tipo != nil && tipo.Kind() == reflect.Ptr {
+		tipo = tipo.Elem()
+	}
+	if tipo == nil || tipo.Kind() != reflect.Struct {
+		return errorNuevo().asignarMotivoSeleccionarTipoDeCampoIncorrecto()
+	}
+
+	esquema, err := bd.leerEsquema(tabla)
+	if err != nil {
+		return err
+	}
+	t, ok := esquema.Tabla(tabla)
+	if !ok {
+		return errorNuevo().asignarMotivoEstructuraIncorrecta(tabla, "la tabla no existe")
+	}
+
+	if diferencias := diferenciasDeEstructura(t, tipo); len(diferencias) > 0 {
+		return errorNuevo().asignarMotivoEstructuraIncorrecta(tabla, strings.Join(diferencias, "; "))
+	}
+
+	return nil
+}
+
+// diferenciasDeEstructura devuelve las diferencias entre los campos de la
+// estructura y las columnas de la tabla, ordenadas por campo.
+func diferenciasDeEstructura(t *Tabla, estructura reflect.Type) []string {
+	var diferencias []string
+	// relación inversa: campo de la estructura → columna de la tabla
+	var columnas = make(map[string]string)
+	for columna, campo := range relacionDeCampos(estructura) {
+		columnas[campo] = columna
+	}
+
+	for i := 0; i < estructura.NumField(); i++ {
+		var campo = estructura.Field(i)
+		nombreColumna, ok := columnas[campo.Name]
+		if !ok {
+			// campo excluido (`bdsql:"-"`)
+			continue
+		}
+
+		columna, ok := t.Columna(nombreColumna)
+		if !ok {
+			diferencias = append(diferencias, fmt.Sprintf("no existe la columna '%v' (campo %v)", nombreColumna, campo.Name))
+			continue
+		}
+		if _, err := funcionDeAsignacion(campo.Type); err != nil {
+			diferencias = append(diferencias, fmt.Sprintf("el campo %v es de un tipo no admitido (%v)", campo.Name, campo.Type))
+			continue
+		}
+		if !tipoCompatible(campo.Type, columna.TipoDeDato) {
+			diferencias = append(diferencias, fmt.Sprintf("la columna '%v' (%v) no es compatible con el campo %v (%v)", columna.Nombre, columna.Tipo, campo.Name, campo.Type))
+		}
+	}
+
+	return diferencias
+}
+
+// tipoCompatible informa si los valores de una columna con el tipo de dato
+// recibido pueden asignarse a un campo del tipo de Go recibido. Los campos
+// de tipo texto y los demás tipos que implementan sql.Scanner admiten
+// cualquier columna.
+func tipoCompatible(tipo reflect.Type, tipoDeDato string) bool {
+	// tipos nulos de database/sql (sql.NullString, sql.NullInt64, etc.): se
+	// verifica el tipo de su valor
+	if reflect.PtrTo(tipo).Implements(tipoScanner) {
+		if valido, ok := tipo.FieldByName("Valid"); ok && tipo.NumField() == 2 && valido.Index[0] == 1 {
+			return tipoCompatible(tipo.Field(0).Type, tipoDeDato)
+		}
+		return true
+	}
+
+	var enteros = map[string]bool{"tinyint": true, "smallint": true, "mediumint": true, "int": true, "bigint": true, "bit": true, "year": true}
+
+	switch tipo.Kind() {
+	case reflect.String:
+		return true
+	case reflect.Bool:
+		return tipoDeDato == "tinyint" || tipoDeDato == "bit"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return enteros[tipoDeDato]
+	case reflect.Float32, reflect.Float64:
+		return enteros[tipoDeDato] || tipoDeDato == "decimal" || tipoDeDato == "float" || tipoDeDato == "double"
+	case reflect.Struct:
+		return tipoDeDato == "date" || tipoDeDato == "datetime" || tipoDeDato == "timestamp"
+	}
+
+	return false
+}
+
+// leerEsquema obtiene la estructura de las tablas de la base de datos de la
+// conexión. Si se recibe el nombre de una tabla, solo se obtiene esa tabla.
+func (bd *BD) leerEsquema(tabla string) (*Esquema, error) {
+	var dialecto = bd.obtenerDialecto()
+	var filtro string
+	var valores []interface{}
+	if tabla != "" {
+		filtro, valores = " and table_name = ?", []interface{}{tabla}
+	}
+
+	var esquema = &Esquema{}
+	var tablas = make(map[string]*Tabla)
+
+	// tablas
+	err := bd.consultarEsquema("select table_name from information_schema.tables where table_schema = database() and table_type = 'BASE TABLE'"+filtro+" order by table_name;", valores,
+		func(filas *sql.Rows) error {
+			var t Tabla
+			if err := filas.Scan(&t.Nombre); err != nil {
+				return err
+			}
+			esquema.Tablas = append(esquema.Tablas, t)
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	for i := range esquema.Tablas {
+		tablas[esquema.Tablas[i].Nombre] = &esquema.Tablas[i]
+	}
+
+	// columnas
+	err = bd.consultarEsquema("select table_name, column_name, column_type, data_type, is_nullable, column_default, extra from information_schema.columns where table_schema = database()"+filtro+" order by table_name, ordinal_position;", valores,
+		func(filas *sql.Rows) error {
+			var nombreTabla, nulo, extra string
+			var c Columna
+			if err := filas.Scan(&nombreTabla, &c.Nombre, &c.Tipo, &c.TipoDeDato, &nulo, &c.PorDefecto, &extra); err != nil {
+				return err
+			}
+			c.TipoDeDato = strings.ToLower(c.TipoDeDato)
+			c.Nulo = nulo == "YES"
+			c.PorDefecto = dialecto.normalizarPorDefecto(c.PorDefecto)
+			c.AutoIncremental = strings.Contains(strings.ToLower(extra), "auto_increment")
+			if t, ok := tablas[nombreTabla]; ok {
+				t.Columnas = append(t.Columnas, c)
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	// clave primaria e índices
+	err = bd.consultarEsquema("select table_name, index_name, non_unique, column_name from information_schema.statistics where table_schema = database()"+filtro+" order by table_name, index_name, seq_in_index;", valores,
+		func(filas *sql.Rows) error {
+			var nombreTabla, nombreIndice, columna string
+			var noUnico int
+			if err := filas.Scan(&nombreTabla, &nombreIndice, &noUnico, &columna); err != nil {
+				return err
+			}
+			t, ok := tablas[nombreTabla]
+			if !ok {
+				return nil
+			}
+			if nombreIndice == "PRIMARY" {
+				t.ClavePrimaria = append(t.ClavePrimaria, columna)
+				return nil
+			}
+			if n := len(t.Indices); n > 0 && t.Indices[n-1].Nombre == nombreIndice {
+				t.Indices[n-1].Columnas = append(t.Indices[n-1].Columnas, columna)
+				return nil
+			}
+			t.Indices = append(t.Indices, Indice{Nombre: nombreIndice, Columnas: []string{columna}, Unico: noUnico == 0})
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	// claves foráneas
+	var filtroClaves string
+	if tabla != "" {
+		filtroClaves = " and k.table_name = ?"
+	}
+	err = bd.consultarEsquema("select k.table_name, k.constraint_name, k.column_name, k.referenced_table_name, k.referenced_column_name, r.delete_rule, r.update_rule "+
+		"from information_schema.key_column_usage k "+
+		"join information_schema.referential_constraints r on r.constraint_schema = k.constraint_schema and r.constraint_name = k.constraint_name and r.table_name = k.table_name "+
+		"where k.table_schema = database() and k.referenced_table_name is not null"+filtroClaves+" order by k.table_name, k.constraint_name, k.ordinal_position;", valores,
+		func(filas *sql.Rows) error {
+			var nombreTabla, nombreClave, columna, tablaReferencia, columnaReferencia, alEliminar, alModificar string
+			if err := filas.Scan(&nombreTabla, &nombreClave, &columna, &tablaReferencia, &columnaReferencia, &alEliminar, &alModificar); err != nil {
+				return err
+			}
+			t, ok := tablas[nombreTabla]
+			if !ok {
+				return nil
+			}
+			if n := len(t.ClavesForaneas); n > 0 && t.ClavesForaneas[n-1].Nombre == nombreClave {
+				t.ClavesForaneas[n-1].Columnas = append(t.ClavesForaneas[n-1].Columnas, columna)
+				t.ClavesForaneas[n-1].ColumnasReferencia = append(t.ClavesForaneas[n-1].ColumnasReferencia, columnaReferencia)
+				return nil
+			}
+			t.ClavesForaneas = append(t.ClavesForaneas, ClaveForanea{
+				Nombre:             nombreClave,
+				Columnas:           []string{columna},
+				TablaReferencia:    tablaReferencia,
+				ColumnasReferencia: []string{columnaReferencia},
+				AlEliminar:         alEliminar,
+				AlModificar:        alModificar,
+			})
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return esquema, nil
+}
+
+// consultarEsquema ejecuta una consulta sobre 'information_schema' e invoca
+// la función recibida por cada registro obtenido.
+func (bd *BD) consultarEsquema(sentencia string, valores []interface{}, leer func(filas *sql.Rows) error) error {
+	filas, err := bd.db.Query(sentencia, valores...)
+	if err != nil {
+		return resolverErrorMysql(err)
+	}
+	defer filas.Close()
+
+	for filas.Next() {
+		if err := leer(filas); err != nil {
+			return errorNuevo().asignarOrigen(err).asignarMotivoSeleccionarLecturaDeCampos()
+		}
+	}
+	if err := filas.Err(); err != nil {
+		return resolverErrorMysql(err)
+	}
+
+	return nil
+}
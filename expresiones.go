@@ -0,0 +1,217 @@
+package bdsql
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+)
+
+// expresion representa una expresión SQL que se incorpora en la sentencia en
+// lugar de un valor. La expresión puede contener parámetros ('?') propios,
+// cuyos valores se envían al motor junto con el resto de los parámetros.
+type expresion struct {
+	sql     string
+	valores []interface{}
+}
+
+// Expr crea una expresión SQL para ser utilizada como valor de un campo en
+// las sentencias 'insert' y 'update'. La expresión se escribe en la
+// sentencia tal cual se recibe; los valores de sus parámetros ('?') se
+// envían al motor como cualquier otro valor.
+//
+//	Ejemplo:
+//	err := bd.
+//		Modificar("personasModificar").
+//		Tabla("personas").
+//		Campos("nombres", "actualizado_en").
+//		Valores("Un nombre", bdsql.Expr("now()")).
+//		Condicion("id = ?", 2).
+//		Ejecutar()
+func Expr(sql string, valores ...interface{}) expresion {
+	return expresion{sql: sql, valores: valores}
+}
+
+// listaVacia es la subconsulta que reemplaza a una lista de valores vacía:
+// 'campo in (...)' resulta falso y 'campo not in (...)' resulta verdadero.
+const listaVacia = "select null from dual where false"
+
+// expandirSentencia reemplaza cada parámetro ('?') de la sentencia cuyo valor
+// deba incorporarse al texto de la sentencia:
+//   - una expresión se reemplaza por su texto, incorporando en su lugar los
+//     valores propios de la expresión.
+//   - una sentencia 'select' se reemplaza por su texto (subconsulta),
+//     incorporando sus valores.
+//   - un predicado se reemplaza por su texto, incorporando sus valores. Un
+//     predicado omitido se reemplaza por una condición verdadera.
+//   - una lista (slice o array) se reemplaza por tantos parámetros como
+//     elementos contenga: 'id in (?)' se convierte en 'id in (?, ?, ?)'. Una
+//     lista vacía se reemplaza por una subconsulta sin registros.
+//
+// Los demás parámetros permanecen sin cambios. Devuelve la sentencia
+// expandida y los valores a enviar al motor.
+func expandirSentencia(sentencia string, valores []interface{}) (string, []interface{}, error) {
+	if !requiereExpansion(valores) {
+		return sentencia, valores, nil
+	}
+
+	var sb strings.Builder
+	var resultado = make([]interface{}, 0, len(valores))
+	var posiciones = parametrosSQL(sentencia)
+	var desde int
+	for i, pos := range posiciones {
+		if i >= len(valores) {
+			break
+		}
+
+		sb.WriteString(sentencia[desde:pos])
+		desde = pos + 1
+
+		switch v := valores[i].(type) {
+		case expresion:
+			s, vs, err := expandirSentencia(v.sql, v.valores)
+			if err != nil {
+				return "", nil, err
+			}
+			sb.WriteString(s)
+			resultado = append(resultado, vs...)
+		case *seleccionar:
+			s, vs, err := v.generarSQLSubconsulta()
+			if err != nil {
+				return "", nil, err
+			}
+			sb.WriteString(s)
+			resultado = append(resultado, vs...)
+		case Predicado:
+			s, vs := v.generarPredicado()
+			if s == "" {
+				// predicado omitido
+				s = "1 = 1"
+			}
+			s, vs, err := expandirSentencia(s, vs)
+			if err != nil {
+				return "", nil, err
+			}
+			sb.WriteString(s)
+			resultado = append(resultado, vs...)
+		default:
+			if !esLista(v) {
+				sb.WriteByte('?')
+				resultado = append(resultado, v)
+				continue
+			}
+			var lista = reflect.ValueOf(v)
+			if lista.Len() == 0 {
+				sb.WriteString(listaVacia)
+				continue
+			}
+			for j := 0; j < lista.Len(); j++ {
+				if j > 0 {
+					sb.WriteString(", ")
+				}
+				sb.WriteByte('?')
+				resultado = append(resultado, lista.Index(j).Interface())
+			}
+		}
+	}
+	sb.WriteString(sentencia[desde:])
+
+	// los valores que no tienen un parámetro asociado se envían tal cual
+	// (será el motor quien informe el error).
+	if len(posiciones) < len(valores) {
+		resultado = append(resultado, valores[len(posiciones):]...)
+	}
+
+	return sb.String(), resultado, nil
+}
+
+// requiereExpansion informa si al menos uno de los valores debe expandirse
+// dentro de la sentencia.
+func requiereExpansion(valores []interface{}) bool {
+	for _, v := range valores {
+		switch v.(type) {
+		case expresion, Predicado, *seleccionar:
+			return true
+		}
+		if esLista(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// esLista informa si el valor es una lista de valores (slice o array) que
+// debe expandirse. No se consideran listas a los valores binarios ([]byte)
+// ni a los tipos que implementan driver.Valuer.
+func esLista(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+	if _, ok := v.(driver.Valuer); ok {
+		return false
+	}
+
+	var tipo = reflect.TypeOf(v)
+	if tipo.Kind() != reflect.Slice && tipo.Kind() != reflect.Array {
+		return false
+	}
+
+	return tipo.Elem().Kind() != reflect.Uint8
+}
+
+// parametrosSQL devuelve las posiciones de los parámetros ('?') de la
+// sentencia, ignorando los que se encuentran dentro de cadenas de texto,
+// identificadores entre comillas y comentarios.
+func parametrosSQL(sentencia string) []int {
+	var posiciones []int
+	recorrerSQL(sentencia, func(i int) int {
+		if sentencia[i] == '?' {
+			posiciones = append(posiciones, i)
+		}
+		return i
+	})
+
+	return posiciones
+}
+
+// recorrerSQL recorre la sentencia invocando a la función por cada caracter
+// que no se encuentre dentro de cadenas de texto, identificadores entre
+// comillas o comentarios. La función recibe la posición del caracter y
+// devuelve la posición desde la cual continuar el recorrido (la posición
+// recibida para continuar con el caracter siguiente).
+func recorrerSQL(sentencia string, f func(i int) int) {
+	for i := 0; i < len(sentencia); i++ {
+		switch c := sentencia[i]; c {
+		case '\'', '"', '`':
+			// cadena de texto o identificador: avanzar hasta el cierre
+			for i++; i < len(sentencia) && sentencia[i] != c; i++ {
+				if sentencia[i] == '\\' && c != '`' {
+					i++
+				}
+			}
+		case '#':
+			// comentario hasta el final de la línea
+			for ; i < len(sentencia) && sentencia[i] != '\n'; i++ {
+			}
+		case '-':
+			if strings.HasPrefix(sentencia[i:], "-- ") {
+				for ; i < len(sentencia) && sentencia[i] != '\n'; i++ {
+				}
+			} else {
+				i = f(i)
+			}
+		case '/':
+			if strings.HasPrefix(sentencia[i:], "/*") {
+				if fin := strings.Index(sentencia[i+2:], "*/"); fin >= 0 {
+					i += fin + 3
+				} else {
+					i = len(sentencia)
+				}
+			} else {
+				i = f(i)
+			}
+		default:
+			i = f(i)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package bdsql
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// interpolarSentencia reemplaza los parámetros ('?') de la sentencia por sus
+// valores representados como literales SQL. La sentencia y los valores deben
+// encontrarse expandidos (expandirSentencia).
+// El resultado se utiliza únicamente para registrar o mostrar la sentencia:
+// las sentencias siempre se ejecutan con parámetros.
+func (bd *BD) interpolarSentencia(sentencia string, valores []interface{}) (string, error) {
+	var posiciones = parametrosSQL(sentencia)
+	if len(posiciones) != len(valores) {
+		return "", errorNuevo().asignarMotivoValorNoInterpolable(
+			fmt.Sprintf("la sentencia tiene %v parámetros y se recibieron %v valores", len(posiciones), len(valores)))
+	}
+
+	var ubicacion = time.UTC
+	bd.mux.Lock()
+	if bd.ubicacion != nil {
+		ubicacion = bd.ubicacion
+	}
+	bd.mux.Unlock()
+
+	var sb strings.Builder
+	var desde int
+	for i, pos := range posiciones {
+		literal, err := literalSQL(valores[i], ubicacion)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(sentencia[desde:pos])
+		sb.WriteString(literal)
+		desde = pos + 1
+	}
+	sb.WriteString(sentencia[desde:])
+
+	return sb.String(), nil
+}
+
+// literalSQL representa el valor como un literal SQL: los textos entre
+// comillas simples y con sus caracteres especiales escapados, los bytes en
+// hexadecimal (x'...') y los momentos como texto en la zona horaria de la
+// conexión, tal como los envía el controlador.
+func literalSQL(valor interface{}, ubicacion *time.Location) (string, error) {
+	// los enteros sin signo superiores a int64 no son admitidos por el
+	// conversor de database/sql, pero sí por el controlador de Mysql
+	if _, ok := valor.(driver.Valuer); !ok && valor != nil {
+		switch v := reflect.ValueOf(valor); v.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return strconv.FormatUint(v.Uint(), 10), nil
+		}
+	}
+
+	convertido, err := driver.DefaultParameterConverter.ConvertValue(valor)
+	if err != nil {
+		return "", errorNuevo().asignarOrigen(err).asignarMotivoValorNoInterpolable(fmt.Sprintf("valor no admitido (%T)", valor))
+	}
+
+	switch v := convertido.(type) {
+	case nil:
+		return "null", nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", errorNuevo().asignarMotivoValorNoInterpolable(fmt.Sprintf("número no representable: %v", v))
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case string:
+		return citarTexto(v), nil
+	case []byte:
+		return "x'" + hex.EncodeToString(v) + "'", nil
+	case time.Time:
+		if v.IsZero() {
+			return "'0000-00-00'", nil
+		}
+		return "'" + v.In(ubicacion).Format("2006-01-02 15:04:05.999999") + "'", nil
+	default:
+		return "", errorNuevo().asignarMotivoValorNoInterpolable(fmt.Sprintf("valor no admitido (%T)", valor))
+	}
+}
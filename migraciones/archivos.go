@@ -0,0 +1,205 @@
+package migraciones
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// archivo representa una migración leída del sistema de archivos.
+type archivo struct {
+	version          int64
+	nombre           string
+	aplicar          string // contenido del archivo '.up.sql'
+	revertir         string // contenido del archivo '.down.sql'
+	tieneRevertir    bool
+	sumaVerificacion string // suma de verificación (sha256) del archivo '.up.sql'
+}
+
+// leerArchivos lee las migraciones del directorio raíz del sistema de
+// archivos, ordenadas por versión. Los archivos se nombran
+// '<version>_<nombre>.up.sql' y '<version>_<nombre>.down.sql'; los demás
+// archivos se ignoran.
+func leerArchivos(sistema fs.FS) ([]archivo, error) {
+	entradas, err := fs.ReadDir(sistema, ".")
+	if err != nil {
+		return nil, errorNuevo().asignarOrigen(err).asignarMotivoArchivoIncorrecto(".", err.Error())
+	}
+
+	var porVersion = make(map[int64]*archivo)
+	for _, entrada := range entradas {
+		if entrada.IsDir() {
+			continue
+		}
+
+		var nombreArchivo = entrada.Name()
+		var base string
+		var esAplicar bool
+		switch {
+		case strings.HasSuffix(nombreArchivo, ".up.sql"):
+			base, esAplicar = strings.TrimSuffix(nombreArchivo, ".up.sql"), true
+		case strings.HasSuffix(nombreArchivo, ".down.sql"):
+			base = strings.TrimSuffix(nombreArchivo, ".down.sql")
+		default:
+			continue
+		}
+
+		var partes = strings.SplitN(base, "_", 2)
+		version, err := strconv.ParseInt(partes[0], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, errorNuevo().asignarMotivoArchivoIncorrecto(nombreArchivo, "el nombre debe comenzar con un número de versión positivo")
+		}
+		var nombre string
+		if len(partes) == 2 {
+			nombre = partes[1]
+		}
+
+		contenido, err := fs.ReadFile(sistema, nombreArchivo)
+		if err != nil {
+			return nil, errorNuevo().asignarOrigen(err).asignarMotivoArchivoIncorrecto(nombreArchivo, err.Error())
+		}
+		if _, err := dividirSentencias(string(contenido)); err != nil {
+			return nil, errorNuevo().asignarMotivoArchivoIncorrecto(nombreArchivo, err.Error())
+		}
+
+		a, ok := porVersion[version]
+		if !ok {
+			a = &archivo{version: version, nombre: nombre}
+			porVersion[version] = a
+		}
+		if a.nombre != nombre {
+			return nil, errorNuevo().asignarMotivoArchivoIncorrecto(nombreArchivo, "existe otra migración con la misma versión")
+		}
+		if esAplicar {
+			if a.sumaVerificacion != "" {
+				return nil, errorNuevo().asignarMotivoArchivoIncorrecto(nombreArchivo, "existe otra migración con la misma versión")
+			}
+			var suma = sha256.Sum256(contenido)
+			a.aplicar, a.sumaVerificacion = string(contenido), hex.EncodeToString(suma[:])
+		} else {
+			a.revertir, a.tieneRevertir = string(contenido), true
+		}
+	}
+
+	var archivos = make([]archivo, 0, len(porVersion))
+	for _, a := range porVersion {
+		if a.sumaVerificacion == "" {
+			return nil, errorNuevo().asignarMotivoArchivoIncorrecto(strconv.FormatInt(a.version, 10), "no existe el archivo '.up.sql'")
+		}
+		archivos = append(archivos, *a)
+	}
+	sort.Slice(archivos, func(i, j int) bool { return archivos[i].version < archivos[j].version })
+
+	return archivos, nil
+}
+
+// dividirSentencias divide el contenido de un archivo en sentencias
+// separadas por ';', ignorando los separadores que se encuentran dentro de
+// cadenas de texto, identificadores entre comillas y comentarios. Se
+// descartan las sentencias vacías.
+// Al igual que el cliente 'mysql', la directiva 'DELIMITER <separador>'
+// (en su propia línea, al comienzo de una sentencia) reemplaza el separador
+// de las sentencias siguientes; permite crear rutinas y disparadores cuyo
+// cuerpo contiene ';':
+//
+//	DELIMITER $$
+//	create trigger ... begin ...; ...; end$$
+//	DELIMITER ;
+func dividirSentencias(contenido string) ([]string, error) {
+	var sentencias []string
+	var desde int
+	var separador = ";"
+	var agregar = func(hasta int) {
+		if s := strings.TrimSpace(contenido[desde:hasta]); s != "" && !esSoloComentario(s) {
+			sentencias = append(sentencias, s)
+		}
+	}
+
+	for i := 0; i < len(contenido); i++ {
+		if (i == 0 || contenido[i-1] == '\n') && esSoloComentario(strings.TrimSpace(contenido[desde:i])) {
+			nuevo, fin, ok := leerDelimitador(contenido[i:])
+			if ok {
+				if nuevo == "" || strings.Contains(nuevo, "\\") {
+					return nil, fmt.Errorf("la directiva 'DELIMITER' requiere un separador válido: %q", strings.TrimSpace(contenido[i:i+fin]))
+				}
+				separador = nuevo
+				i += fin
+				desde = i
+				continue
+			}
+		}
+		if strings.HasPrefix(contenido[i:], separador) {
+			agregar(i)
+			i += len(separador) - 1
+			desde = i + 1
+			continue
+		}
+
+		switch c := contenido[i]; c {
+		case '\'', '"', '`':
+			// cadena de texto o identificador: avanzar hasta el cierre
+			for i++; i < len(contenido) && contenido[i] != c; i++ {
+				if contenido[i] == '\\' && c != '`' {
+					i++
+				}
+			}
+		case '#':
+			for ; i < len(contenido) && contenido[i] != '\n'; i++ {
+			}
+		case '-':
+			if strings.HasPrefix(contenido[i:], "-- ") || strings.HasPrefix(contenido[i:], "--\n") {
+				for ; i < len(contenido) && contenido[i] != '\n'; i++ {
+				}
+			}
+		case '/':
+			if strings.HasPrefix(contenido[i:], "/*") {
+				if fin := strings.Index(contenido[i+2:], "*/"); fin >= 0 {
+					i += fin + 3
+				} else {
+					i = len(contenido)
+				}
+			}
+		}
+	}
+	if desde < len(contenido) {
+		agregar(len(contenido))
+	}
+
+	return sentencias, nil
+}
+
+// leerDelimitador informa si la línea que comienza el texto es una directiva
+// 'DELIMITER' y devuelve el nuevo separador y la posición del fin de la
+// línea.
+func leerDelimitador(texto string) (string, int, bool) {
+	var fin = strings.IndexByte(texto, '\n')
+	if fin < 0 {
+		fin = len(texto)
+	}
+	var campos = strings.Fields(texto[:fin])
+	if len(campos) == 0 || !strings.EqualFold(campos[0], "delimiter") {
+		return "", fin, false
+	}
+	if len(campos) != 2 {
+		return "", fin, true
+	}
+
+	return campos[1], fin, true
+}
+
+// esSoloComentario informa si el texto contiene únicamente comentarios de
+// línea.
+func esSoloComentario(texto string) bool {
+	for _, linea := range strings.Split(texto, "\n") {
+		linea = strings.TrimSpace(linea)
+		if linea != "" && !strings.HasPrefix(linea, "--") && !strings.HasPrefix(linea, "#") {
+			return false
+		}
+	}
+
+	return true
+}
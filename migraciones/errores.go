@@ -0,0 +1,89 @@
+package migraciones
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EsError devuelve el error del paquete y un valor lógico que confirma el tipo.
+func EsError(err error) (*errorMigracion, bool) {
+	em, ok := err.(*errorMigracion)
+	return em, ok
+}
+
+type errorMigracion struct {
+	// origen (causa) del error (error original de la base de datos)
+	origen error
+
+	// mensajes de error
+	mensajes []string
+
+	// los diversos motivos (causas) del origen del error
+	errorMotivos struct {
+		esArchivoIncorrecto   bool // el nombre o el contenido de un archivo de migración no es válido
+		esArchivoModificado   bool // un archivo de una migración aplicada fue modificado
+		esArchivoFaltante     bool // no existe el archivo de reversión ('.down.sql') de una migración aplicada
+		esBloqueoNoDisponible bool // otro proceso se encuentra aplicando migraciones
+		esEjecucion           bool // error al ejecutar una migración o al registrarla
+		esTablaDeControl      bool // error al crear o leer la tabla de control de las migraciones aplicadas
+	}
+}
+
+// Error devuelve el mensaje de error.
+func (err *errorMigracion) Error() string {
+	return strings.Join(err.mensajes, ". ")
+}
+
+// ObtenerOrigen devuelve el error de origen (error original).
+func (err *errorMigracion) ObtenerOrigen() error {
+	return err.origen
+}
+
+func (err *errorMigracion) EsArchivoIncorrecto() bool { return err.errorMotivos.esArchivoIncorrecto }
+func (err *errorMigracion) EsArchivoModificado() bool { return err.errorMotivos.esArchivoModificado }
+func (err *errorMigracion) EsArchivoFaltante() bool   { return err.errorMotivos.esArchivoFaltante }
+func (err *errorMigracion) EsBloqueoNoDisponible() bool {
+	return err.errorMotivos.esBloqueoNoDisponible
+}
+func (err *errorMigracion) EsEjecucion() bool      { return err.errorMotivos.esEjecucion }
+func (err *errorMigracion) EsTablaDeControl() bool { return err.errorMotivos.esTablaDeControl }
+
+func (err *errorMigracion) asignarOrigen(origen error) *errorMigracion {
+	err.origen = origen
+	return err
+}
+
+func (err *errorMigracion) asignarMotivoArchivoIncorrecto(archivo, motivo string) *errorMigracion {
+	err.mensajes = append(err.mensajes, fmt.Sprintf("El archivo de migración '%v' no es válido: %v", archivo, motivo))
+	err.errorMotivos.esArchivoIncorrecto = true
+	return err
+}
+func (err *errorMigracion) asignarMotivoArchivoModificado(versiones string) *errorMigracion {
+	err.mensajes = append(err.mensajes, fmt.Sprintf("Los archivos de las migraciones aplicadas fueron modificados: %v", versiones))
+	err.errorMotivos.esArchivoModificado = true
+	return err
+}
+func (err *errorMigracion) asignarMotivoArchivoFaltante(version int64) *errorMigracion {
+	err.mensajes = append(err.mensajes, fmt.Sprintf("No existe el archivo de reversión de la migración aplicada: %v", version))
+	err.errorMotivos.esArchivoFaltante = true
+	return err
+}
+func (err *errorMigracion) asignarMotivoBloqueoNoDisponible() *errorMigracion {
+	err.mensajes = append(err.mensajes, "No es posible obtener el bloqueo de migraciones: otro proceso se encuentra aplicando migraciones")
+	err.errorMotivos.esBloqueoNoDisponible = true
+	return err
+}
+func (err *errorMigracion) asignarMotivoEjecucion(version int64) *errorMigracion {
+	err.mensajes = append(err.mensajes, fmt.Sprintf("No es posible ejecutar la migración %v: %v", version, err.origen))
+	err.errorMotivos.esEjecucion = true
+	return err
+}
+func (err *errorMigracion) asignarMotivoTablaDeControl() *errorMigracion {
+	err.mensajes = append(err.mensajes, fmt.Sprintf("No es posible crear o leer la tabla de control de las migraciones: %v", err.origen))
+	err.errorMotivos.esTablaDeControl = true
+	return err
+}
+
+func errorNuevo() *errorMigracion {
+	return &errorMigracion{}
+}
@@ -0,0 +1,384 @@
+/*
+Package migraciones aplica y revierte las migraciones del esquema de la base
+de datos a partir de archivos SQL.
+
+Las migraciones se leen de un sistema de archivos (fs.FS), lo cual permite
+incorporarlas en el ejecutable con 'embed'. Cada migración se compone de un
+archivo '<version>_<nombre>.up.sql' y, opcionalmente, de un archivo
+'<version>_<nombre>.down.sql' para revertirla:
+
+	0001_crear_personas.up.sql
+	0001_crear_personas.down.sql
+	0002_agregar_telefonos.up.sql
+	0002_agregar_telefonos.down.sql
+
+Las migraciones aplicadas se registran en una tabla de control junto con la
+suma de verificación de su archivo: si un archivo ya aplicado es modificado,
+no se aplican nuevas migraciones y se devuelve un error.
+
+En el modo simulación de la base de datos (bdsql.BD.ModoSimulacion), las
+sentencias de las migraciones, su registro y la creación de la tabla de
+control se escriben en la salida de la simulación y no se ejecutan: las
+migraciones devueltas se informan como simuladas y no como aplicadas o
+revertidas.
+
+	//go:embed migraciones/*.sql
+	var archivos embed.FS
+
+	sub, _ := fs.Sub(archivos, "migraciones")
+	aplicadas, err := migraciones.Nuevo(bd, sub).Aplicar()
+*/
+package migraciones
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fabianpallares/bdsql"
+	"github.com/go-sql-driver/mysql"
+)
+
+// Migracion representa una migración del esquema de la base de datos.
+type Migracion struct {
+	Version          int64     // versión de la migración
+	Nombre           string    // nombre de la migración (obtenido del nombre del archivo)
+	SumaVerificacion string    // suma de verificación (sha256) del archivo '.up.sql'
+	Aplicada         bool      // la migración se encuentra aplicada
+	AplicadaEn       time.Time // momento en que se aplicó la migración
+	Simulada         bool      // la aplicación o la reversión fue simulada (modo simulación)
+}
+
+// Migrador aplica y revierte las migraciones del esquema de la base de datos.
+type Migrador struct {
+	bd       *bdsql.BD
+	archivos fs.FS
+
+	tabla         string        // tabla de control de las migraciones aplicadas
+	esperaBloqueo time.Duration // espera máxima del bloqueo de migraciones
+	transaccional bool          // ejecutar cada migración dentro de una transacción
+}
+
+// Nuevo crea un migrador con las migraciones del sistema de archivos
+// recibido (los archivos se leen de su directorio raíz).
+func Nuevo(bd *bdsql.BD, archivos fs.FS) *Migrador {
+	return &Migrador{
+		bd:            bd,
+		archivos:      archivos,
+		tabla:         "migraciones_esquema",
+		esperaBloqueo: time.Minute,
+		transaccional: true,
+	}
+}
+
+// Tabla establece el nombre de la tabla de control de las migraciones
+// aplicadas. Por defecto es 'migraciones_esquema'.
+func (m *Migrador) Tabla(tabla string) *Migrador {
+	m.tabla = tabla
+	return m
+}
+
+// EsperaBloqueo establece la espera máxima para obtener el bloqueo de
+// migraciones. El bloqueo ('get_lock') evita que dos procesos apliquen las
+// migraciones al mismo tiempo. Por defecto es un minuto.
+func (m *Migrador) EsperaBloqueo(espera time.Duration) *Migrador {
+	m.esperaBloqueo = espera
+	return m
+}
+
+// SinTransaccion establece que las migraciones no se ejecuten dentro de una
+// transacción.
+// Mysql y Mariadb confirman implícitamente la transacción al ejecutar
+// sentencias DDL ('create', 'alter', 'drop'): la transacción solo garantiza
+// la atomicidad de las sentencias DML de la migración y de su registro en la
+// tabla de control. Si una migración con sentencias DDL falla, las
+// sentencias ya ejecutadas deben revertirse manualmente.
+func (m *Migrador) SinTransaccion() *Migrador {
+	m.transaccional = false
+	return m
+}
+
+// Estado devuelve todas las migraciones (de los archivos y de la tabla de
+// control) ordenadas por versión, informando cuáles se encuentran aplicadas.
+// La tabla de control no se crea: si no existe, no existen migraciones
+// aplicadas.
+func (m *Migrador) Estado() ([]Migracion, error) {
+	var ctx = context.Background()
+	con, err := m.bd.DB().Conn(ctx)
+	if err != nil {
+		return nil, errorNuevo().asignarOrigen(err).asignarMotivoTablaDeControl()
+	}
+	defer con.Close()
+
+	archivos, err := leerArchivos(m.archivos)
+	if err != nil {
+		return nil, err
+	}
+	aplicadas, err := m.leerAplicadas(ctx, con, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var estado []Migracion
+	for _, a := range archivos {
+		if aplicada, ok := aplicadas[a.version]; ok {
+			estado = append(estado, aplicada)
+			delete(aplicadas, a.version)
+			continue
+		}
+		estado = append(estado, Migracion{Version: a.version, Nombre: a.nombre, SumaVerificacion: a.sumaVerificacion})
+	}
+	// migraciones aplicadas cuyos archivos no existen
+	for _, aplicada := range aplicadas {
+		estado = append(estado, aplicada)
+	}
+	sort.Slice(estado, func(i, j int) bool { return estado[i].Version < estado[j].Version })
+
+	return estado, nil
+}
+
+// Aplicar aplica las migraciones pendientes en orden de versión y devuelve
+// las migraciones aplicadas. Si los archivos de las migraciones ya aplicadas
+// fueron modificados, no se aplica ninguna migración.
+func (m *Migrador) Aplicar() ([]Migracion, error) {
+	var aplicadas []Migracion
+	err := m.ejecutar(func(ctx context.Context, con *sql.Conn, archivos []archivo, registradas map[int64]Migracion) error {
+		var simulada = m.bd.EnSimulacion()
+		for _, a := range archivos {
+			if _, ok := registradas[a.version]; ok {
+				continue
+			}
+			var registrar = fmt.Sprintf("insert into %v (version, nombre, suma_verificacion) values (?, ?, ?);", m.tabla)
+			if err := m.ejecutarMigracion(ctx, con, a.version, a.aplicar, registrar, a.version, a.nombre, a.sumaVerificacion); err != nil {
+				return err
+			}
+			var aplicada = Migracion{Version: a.version, Nombre: a.nombre, SumaVerificacion: a.sumaVerificacion, Simulada: simulada}
+			if !simulada {
+				aplicada.Aplicada, aplicada.AplicadaEn = true, time.Now()
+			}
+			aplicadas = append(aplicadas, aplicada)
+		}
+		return nil
+	})
+
+	return aplicadas, err
+}
+
+// RevertirHasta revierte, en orden descendente de versión, las migraciones
+// aplicadas cuya versión es mayor a la versión recibida, y devuelve las
+// migraciones revertidas. Con la versión 0 se revierten todas las
+// migraciones. Cada migración revertida debe contar con su archivo
+// '.down.sql'.
+func (m *Migrador) RevertirHasta(version int64) ([]Migracion, error) {
+	var revertidas []Migracion
+	err := m.ejecutar(func(ctx context.Context, con *sql.Conn, archivos []archivo, registradas map[int64]Migracion) error {
+		var porVersion = make(map[int64]archivo, len(archivos))
+		for _, a := range archivos {
+			porVersion[a.version] = a
+		}
+		var versiones []int64
+		for v := range registradas {
+			if v > version {
+				versiones = append(versiones, v)
+			}
+		}
+		sort.Slice(versiones, func(i, j int) bool { return versiones[i] > versiones[j] })
+
+		// verificar que existan todos los archivos de reversión antes de
+		// revertir la primera migración
+		for _, v := range versiones {
+			if a, ok := porVersion[v]; !ok || !a.tieneRevertir {
+				return errorNuevo().asignarMotivoArchivoFaltante(v)
+			}
+		}
+		var simulada = m.bd.EnSimulacion()
+		for _, v := range versiones {
+			var registrar = fmt.Sprintf("delete from %v where version = ?;", m.tabla)
+			if err := m.ejecutarMigracion(ctx, con, v, porVersion[v].revertir, registrar, v); err != nil {
+				return err
+			}
+			var revertida = registradas[v]
+			revertida.Aplicada, revertida.Simulada = simulada, simulada
+			revertidas = append(revertidas, revertida)
+		}
+		return nil
+	})
+
+	return revertidas, err
+}
+
+// ejecutar obtiene una conexión y el bloqueo de migraciones, verifica los
+// archivos de las migraciones aplicadas y ejecuta la función recibida.
+func (m *Migrador) ejecutar(f func(ctx context.Context, con *sql.Conn, archivos []archivo, registradas map[int64]Migracion) error) error {
+	archivos, err := leerArchivos(m.archivos)
+	if err != nil {
+		return err
+	}
+
+	// el bloqueo pertenece a la conexión: todas las sentencias se ejecutan
+	// sobre la misma conexión
+	var ctx = context.Background()
+	con, err := m.bd.DB().Conn(ctx)
+	if err != nil {
+		return errorNuevo().asignarOrigen(err).asignarMotivoTablaDeControl()
+	}
+	defer con.Close()
+
+	if err := m.bloquear(ctx, con); err != nil {
+		return err
+	}
+	defer m.liberar(ctx, con)
+
+	if err := m.crearTabla(ctx, con); err != nil {
+		return err
+	}
+	// en el modo simulación la creación de la tabla de control no se
+	// ejecuta: la tabla puede no existir
+	registradas, err := m.leerAplicadas(ctx, con, m.bd.EnSimulacion())
+	if err != nil {
+		return err
+	}
+
+	// verificar que los archivos de las migraciones aplicadas no fueron
+	// modificados
+	var modificadas []string
+	for _, a := range archivos {
+		if r, ok := registradas[a.version]; ok && r.SumaVerificacion != a.sumaVerificacion {
+			modificadas = append(modificadas, fmt.Sprintf("%v_%v", a.version, a.nombre))
+		}
+	}
+	if len(modificadas) > 0 {
+		return errorNuevo().asignarMotivoArchivoModificado(strings.Join(modificadas, ", "))
+	}
+
+	return f(ctx, con, archivos, registradas)
+}
+
+// ejecutarMigracion ejecuta las sentencias de la migración y la sentencia de
+// registro en la tabla de control, dentro de una transacción si el migrador
+// es transaccional. Las sentencias se ejecutan con ExecContext de la base de
+// datos, que respeta el modo simulación.
+func (m *Migrador) ejecutarMigracion(ctx context.Context, con *sql.Conn, version int64, contenido, registrar string, valores ...interface{}) error {
+	sentencias, err := dividirSentencias(contenido)
+	if err != nil {
+		return errorNuevo().asignarOrigen(err).asignarMotivoEjecucion(version)
+	}
+
+	if !m.transaccional {
+		var sesion = m.bd.Sesion(con)
+		for _, sentencia := range sentencias {
+			if _, err := sesion.ExecContext(ctx, sentencia); err != nil {
+				return errorNuevo().asignarOrigen(err).asignarMotivoEjecucion(version)
+			}
+		}
+		if _, err := sesion.ExecContext(ctx, registrar, valores...); err != nil {
+			return errorNuevo().asignarOrigen(err).asignarMotivoEjecucion(version)
+		}
+		return nil
+	}
+
+	tx, err := con.BeginTx(ctx, nil)
+	if err != nil {
+		return errorNuevo().asignarOrigen(err).asignarMotivoEjecucion(version)
+	}
+	var sesion = m.bd.Sesion(tx)
+	for _, sentencia := range sentencias {
+		if _, err := sesion.ExecContext(ctx, sentencia); err != nil {
+			tx.Rollback()
+			return errorNuevo().asignarOrigen(err).asignarMotivoEjecucion(version)
+		}
+	}
+	if _, err := sesion.ExecContext(ctx, registrar, valores...); err != nil {
+		tx.Rollback()
+		return errorNuevo().asignarOrigen(err).asignarMotivoEjecucion(version)
+	}
+	if err := tx.Commit(); err != nil {
+		return errorNuevo().asignarOrigen(err).asignarMotivoEjecucion(version)
+	}
+
+	return nil
+}
+
+// bloquear obtiene el bloqueo de migraciones ('get_lock'), esperando como
+// máximo la espera establecida.
+func (m *Migrador) bloquear(ctx context.Context, con *sql.Conn) error {
+	var obtenido sql.NullInt64
+	err := con.QueryRowContext(ctx, "select get_lock(?, ?);", m.obtenerNombreBloqueo(), int64(m.esperaBloqueo/time.Second)).Scan(&obtenido)
+	if err != nil {
+		return errorNuevo().asignarOrigen(err).asignarMotivoBloqueoNoDisponible()
+	}
+	if !obtenido.Valid || obtenido.Int64 != 1 {
+		return errorNuevo().asignarMotivoBloqueoNoDisponible()
+	}
+
+	return nil
+}
+
+// liberar libera el bloqueo de migraciones.
+func (m *Migrador) liberar(ctx context.Context, con *sql.Conn) {
+	con.ExecContext(ctx, "select release_lock(?);", m.obtenerNombreBloqueo())
+}
+
+// obtenerNombreBloqueo devuelve el nombre del bloqueo de migraciones: se
+// incorpora el nombre de la tabla de control para que los migradores de
+// distintas tablas no se bloqueen entre sí.
+func (m *Migrador) obtenerNombreBloqueo() string {
+	return "bdsql.migraciones." + m.tabla
+}
+
+// crearTabla crea la tabla de control de las migraciones aplicadas, si no
+// existe. En el modo simulación la tabla no se crea.
+func (m *Migrador) crearTabla(ctx context.Context, con *sql.Conn) error {
+	var sentencia = fmt.Sprintf(`create table if not exists %v (
+	version bigint not null primary key,
+	nombre varchar(255) not null,
+	suma_verificacion char(64) not null,
+	aplicada_en datetime not null default current_timestamp
+);`, m.tabla)
+	if _, err := m.bd.Sesion(con).ExecContext(ctx, sentencia); err != nil {
+		return errorNuevo().asignarOrigen(err).asignarMotivoTablaDeControl()
+	}
+
+	return nil
+}
+
+// leerAplicadas devuelve las migraciones registradas en la tabla de control.
+// Si la tabla no existe y se acepta su inexistencia (tablaOpcional), no
+// existen migraciones aplicadas; en caso contrario se devuelve el error.
+func (m *Migrador) leerAplicadas(ctx context.Context, con *sql.Conn, tablaOpcional bool) (map[int64]Migracion, error) {
+	filas, err := con.QueryContext(ctx, fmt.Sprintf("select version, nombre, suma_verificacion, aplicada_en from %v;", m.tabla))
+	if e, ok := err.(*mysql.MySQLError); ok && e.Number == 1146 && tablaOpcional {
+		return make(map[int64]Migracion), nil
+	}
+	if err != nil {
+		return nil, errorNuevo().asignarOrigen(err).asignarMotivoTablaDeControl()
+	}
+	defer filas.Close()
+
+	var aplicadas = make(map[int64]Migracion)
+	for filas.Next() {
+		var migracion = Migracion{Aplicada: true}
+		var aplicadaEn interface{}
+		if err := filas.Scan(&migracion.Version, &migracion.Nombre, &migracion.SumaVerificacion, &aplicadaEn); err != nil {
+			return nil, errorNuevo().asignarOrigen(err).asignarMotivoTablaDeControl()
+		}
+		// la fecha se recibe como time.Time solo si la conexión utiliza
+		// 'parseTime=true'
+		switch v := aplicadaEn.(type) {
+		case time.Time:
+			migracion.AplicadaEn = v
+		case []byte:
+			migracion.AplicadaEn, _ = time.Parse("2006-01-02 15:04:05", string(v))
+		}
+		aplicadas[migracion.Version] = migracion
+	}
+	if err := filas.Err(); err != nil {
+		return nil, errorNuevo().asignarOrigen(err).asignarMotivoTablaDeControl()
+	}
+
+	return aplicadas, nil
+}
@@ -0,0 +1,292 @@
+package migraciones
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/fabianpallares/bdsql/bdsqltest"
+)
+
+func TestLeerArchivos(t *testing.T) {
+	sistema := fstest.MapFS{
+		"0002_agregar_telefonos.up.sql":   {Data: []byte("alter table personas add telefono varchar(30);")},
+		"0002_agregar_telefonos.down.sql": {Data: []byte("alter table personas drop telefono;")},
+		"0001_crear_personas.up.sql":      {Data: []byte("create table personas (id int primary key);")},
+		"LEAME.md":                        {Data: []byte("no es una migración")},
+	}
+
+	archivos, err := leerArchivos(sistema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archivos) != 2 || archivos[0].version != 1 || archivos[1].version != 2 {
+		t.Fatalf("migraciones incorrectas: %+v", archivos)
+	}
+	if archivos[0].nombre != "crear_personas" || archivos[0].tieneRevertir || !archivos[1].tieneRevertir {
+		t.Errorf("migraciones incorrectas: %+v", archivos)
+	}
+	if len(archivos[0].sumaVerificacion) != 64 {
+		t.Errorf("suma de verificación incorrecta: %v", archivos[0].sumaVerificacion)
+	}
+
+	// versiones repetidas
+	sistema["0002_otra.up.sql"] = &fstest.MapFile{Data: []byte("select 1;")}
+	_, err = leerArchivos(sistema)
+	if e, ok := EsError(err); !ok || !e.EsArchivoIncorrecto() {
+		t.Errorf("se esperaba el error de archivo incorrecto: %v", err)
+	}
+}
+
+func TestDividirSentencias(t *testing.T) {
+	contenido := `-- personas
+create table personas (
+	id int primary key,
+	nombre varchar(50) default 'a;b' -- comentario; con separador
+);
+/* comentario; de bloque */
+insert into personas values (1, "x;y");
+# comentario final;
+`
+	sentencias, err := dividirSentencias(contenido)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sentencias) != 2 {
+		t.Fatalf("cantidad de sentencias incorrecta: %q", sentencias)
+	}
+	if sentencias[1] != `/* comentario; de bloque */
+insert into personas values (1, "x;y")` {
+		t.Errorf("sentencia incorrecta: %q", sentencias[1])
+	}
+}
+
+func TestDividirSentenciasConDelimitador(t *testing.T) {
+	contenido := `-- disparador
+DELIMITER $$
+create trigger personas_ai after insert on personas for each row
+begin
+	insert into auditoria values (new.id, 'alta;');
+	update contadores set total = total + 1;
+end$$
+delimiter ;
+insert into personas values (1, 'x');
+`
+	sentencias, err := dividirSentencias(contenido)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sentencias) != 2 {
+		t.Fatalf("cantidad de sentencias incorrecta: %q", sentencias)
+	}
+	if sentencias[0] != `create trigger personas_ai after insert on personas for each row
+begin
+	insert into auditoria values (new.id, 'alta;');
+	update contadores set total = total + 1;
+end` || sentencias[1] != "insert into personas values (1, 'x')" {
+		t.Errorf("sentencias incorrectas: %q", sentencias)
+	}
+
+	// la directiva sin separador no es válida
+	sistema := fstest.MapFS{
+		"0001_disparador.up.sql": {Data: []byte("DELIMITER\ncreate trigger t before insert on p for each row set new.a = 1;")},
+	}
+	_, err = leerArchivos(sistema)
+	if e, ok := EsError(err); !ok || !e.EsArchivoIncorrecto() {
+		t.Errorf("se esperaba el error de archivo incorrecto: %v", err)
+	}
+}
+
+// migracionesDePrueba devuelve las migraciones utilizadas por las pruebas
+// del migrador y sus sumas de verificación.
+func migracionesDePrueba(t *testing.T) (fstest.MapFS, []archivo) {
+	sistema := fstest.MapFS{
+		"0001_crear_personas.up.sql":      {Data: []byte("create table personas (id int primary key);")},
+		"0001_crear_personas.down.sql":    {Data: []byte("drop table personas;")},
+		"0002_agregar_telefonos.up.sql":   {Data: []byte("alter table personas add telefono varchar(30);\nupdate personas set telefono = '';")},
+		"0002_agregar_telefonos.down.sql": {Data: []byte("alter table personas drop telefono;")},
+	}
+	archivos, err := leerArchivos(sistema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return sistema, archivos
+}
+
+// esperarInicio registra las sentencias que ejecuta el migrador antes de
+// aplicar o revertir las migraciones: el bloqueo, la creación de la tabla de
+// control y la lectura de las migraciones registradas.
+func esperarInicio(ctrl *bdsqltest.Controlador, registradas *bdsqltest.Filas) {
+	ctrl.Esperar("select get_lock(?, ?);").
+		ConValores("bdsql.migraciones.migraciones_esquema", 60).
+		DevolverFilas(bdsqltest.NuevasFilas("get_lock").Agregar(1))
+	ctrl.EsperarPatron(`^create table if not exists migraciones_esquema \(`)
+	ctrl.Esperar("select version, nombre, suma_verificacion, aplicada_en from migraciones_esquema;").
+		DevolverFilas(registradas)
+}
+
+func TestAplicar(t *testing.T) {
+	sistema, archivos := migracionesDePrueba(t)
+	bd, ctrl := bdsqltest.Nuevo(t)
+
+	// la migración 1 se encuentra aplicada: solo se aplica la migración 2
+	esperarInicio(ctrl, bdsqltest.NuevasFilas("version", "nombre", "suma_verificacion", "aplicada_en").
+		Agregar(1, "crear_personas", archivos[0].sumaVerificacion, "2024-01-02 03:04:05"))
+	ctrl.Esperar("alter table personas add telefono varchar(30)")
+	ctrl.Esperar("update personas set telefono = ''")
+	ctrl.Esperar("insert into migraciones_esquema (version, nombre, suma_verificacion) values (?, ?, ?);").
+		ConValores(2, "agregar_telefonos", archivos[1].sumaVerificacion)
+	ctrl.Esperar("select release_lock(?);").ConValores("bdsql.migraciones.migraciones_esquema")
+
+	aplicadas, err := Nuevo(bd, sistema).Aplicar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aplicadas) != 1 || aplicadas[0].Version != 2 || !aplicadas[0].Aplicada {
+		t.Errorf("migraciones aplicadas incorrectas: %+v", aplicadas)
+	}
+}
+
+func TestAplicarConArchivoModificado(t *testing.T) {
+	sistema, _ := migracionesDePrueba(t)
+	bd, ctrl := bdsqltest.Nuevo(t)
+
+	// la suma de verificación registrada no coincide con la del archivo: no
+	// se aplica ninguna migración
+	esperarInicio(ctrl, bdsqltest.NuevasFilas("version", "nombre", "suma_verificacion", "aplicada_en").
+		Agregar(1, "crear_personas", "0000", "2024-01-02 03:04:05"))
+	ctrl.Esperar("select release_lock(?);")
+
+	aplicadas, err := Nuevo(bd, sistema).Aplicar()
+	if e, ok := EsError(err); !ok || !e.EsArchivoModificado() {
+		t.Errorf("se esperaba el error de archivo modificado: %v", err)
+	}
+	if len(aplicadas) != 0 {
+		t.Errorf("no se esperaban migraciones aplicadas: %+v", aplicadas)
+	}
+}
+
+func TestRevertirHasta(t *testing.T) {
+	sistema, archivos := migracionesDePrueba(t)
+	bd, ctrl := bdsqltest.Nuevo(t)
+
+	esperarInicio(ctrl, bdsqltest.NuevasFilas("version", "nombre", "suma_verificacion", "aplicada_en").
+		Agregar(1, "crear_personas", archivos[0].sumaVerificacion, "2024-01-02 03:04:05").
+		Agregar(2, "agregar_telefonos", archivos[1].sumaVerificacion, "2024-01-02 03:04:05"))
+	ctrl.Esperar("alter table personas drop telefono")
+	ctrl.Esperar("delete from migraciones_esquema where version = ?;").ConValores(2)
+	ctrl.Esperar("drop table personas")
+	ctrl.Esperar("delete from migraciones_esquema where version = ?;").ConValores(1)
+	ctrl.Esperar("select release_lock(?);")
+
+	revertidas, err := Nuevo(bd, sistema).RevertirHasta(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revertidas) != 2 || revertidas[0].Version != 2 || revertidas[1].Version != 1 || revertidas[0].Aplicada {
+		t.Errorf("migraciones revertidas incorrectas: %+v", revertidas)
+	}
+}
+
+func TestAplicarSinTablaDeControl(t *testing.T) {
+	sistema, _ := migracionesDePrueba(t)
+	bd, ctrl := bdsqltest.Nuevo(t)
+
+	// fuera del modo simulación la tabla de control fue creada: su
+	// inexistencia es un error y no se aplica ninguna migración
+	ctrl.Esperar("select get_lock(?, ?);").
+		DevolverFilas(bdsqltest.NuevasFilas("get_lock").Agregar(1))
+	ctrl.EsperarPatron(`^create table if not exists migraciones_esquema \(`)
+	ctrl.Esperar("select version, nombre, suma_verificacion, aplicada_en from migraciones_esquema;").
+		DevolverError(bdsqltest.ErrorMysql(1146, "Table 'prueba.migraciones_esquema' doesn't exist"))
+	ctrl.Esperar("select release_lock(?);")
+
+	aplicadas, err := Nuevo(bd, sistema).Aplicar()
+	if e, ok := EsError(err); !ok || !e.EsTablaDeControl() {
+		t.Errorf("se esperaba el error de tabla de control: %v", err)
+	}
+	if len(aplicadas) != 0 {
+		t.Errorf("no se esperaban migraciones aplicadas: %+v", aplicadas)
+	}
+}
+
+func TestEstado(t *testing.T) {
+	sistema, archivos := migracionesDePrueba(t)
+	bd, ctrl := bdsqltest.Nuevo(t)
+
+	// la migración 1 se encuentra aplicada
+	ctrl.Esperar("select version, nombre, suma_verificacion, aplicada_en from migraciones_esquema;").
+		DevolverFilas(bdsqltest.NuevasFilas("version", "nombre", "suma_verificacion", "aplicada_en").
+			Agregar(1, "crear_personas", archivos[0].sumaVerificacion, "2024-01-02 03:04:05"))
+
+	estado, err := Nuevo(bd, sistema).Estado()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(estado) != 2 || !estado[0].Aplicada || estado[1].Aplicada || estado[1].Version != 2 {
+		t.Errorf("estado incorrecto: %+v", estado)
+	}
+
+	// la tabla de control no existe: no se crea y no existen migraciones
+	// aplicadas
+	ctrl.Esperar("select version, nombre, suma_verificacion, aplicada_en from migraciones_esquema;").
+		DevolverError(bdsqltest.ErrorMysql(1146, "Table 'prueba.migraciones_esquema' doesn't exist"))
+
+	estado, err = Nuevo(bd, sistema).Estado()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(estado) != 2 || estado[0].Aplicada || estado[1].Aplicada {
+		t.Errorf("estado incorrecto: %+v", estado)
+	}
+}
+
+func TestBloqueoNoDisponible(t *testing.T) {
+	sistema, _ := migracionesDePrueba(t)
+	bd, ctrl := bdsqltest.Nuevo(t)
+
+	// otro proceso mantiene el bloqueo: 'get_lock' devuelve 0 al vencer la
+	// espera y no se ejecuta ninguna otra sentencia
+	ctrl.Esperar("select get_lock(?, ?);").
+		ConValores("bdsql.migraciones.personas_migraciones", 5).
+		DevolverFilas(bdsqltest.NuevasFilas("get_lock").Agregar(0))
+
+	_, err := Nuevo(bd, sistema).Tabla("personas_migraciones").EsperaBloqueo(5 * time.Second).Aplicar()
+	if e, ok := EsError(err); !ok || !e.EsBloqueoNoDisponible() {
+		t.Errorf("se esperaba el error de bloqueo no disponible: %v", err)
+	}
+}
+
+func TestAplicarEnModoSimulacion(t *testing.T) {
+	sistema, archivos := migracionesDePrueba(t)
+	bd, ctrl := bdsqltest.Nuevo(t)
+	var salida strings.Builder
+	bd.ModoSimulacion(true).SalidaSimulacion(&salida)
+
+	// la tabla de control no se crea: no existen migraciones aplicadas
+	ctrl.Esperar("select get_lock(?, ?);").
+		DevolverFilas(bdsqltest.NuevasFilas("get_lock").Agregar(1))
+	ctrl.Esperar("select version, nombre, suma_verificacion, aplicada_en from migraciones_esquema;").
+		DevolverError(bdsqltest.ErrorMysql(1146, "Table 'prueba.migraciones_esquema' doesn't exist"))
+	ctrl.Esperar("select release_lock(?);")
+
+	aplicadas, err := Nuevo(bd, sistema).Aplicar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aplicadas) != 2 || !aplicadas[0].Simulada || aplicadas[0].Aplicada || !aplicadas[0].AplicadaEn.IsZero() {
+		t.Errorf("migraciones simuladas incorrectas: %+v", aplicadas)
+	}
+	for _, esperada := range []string{
+		"create table if not exists migraciones_esquema (",
+		"create table personas (id int primary key)\n",
+		"insert into migraciones_esquema (version, nombre, suma_verificacion) values (1, 'crear_personas', '" + archivos[0].sumaVerificacion + "');",
+		"update personas set telefono = ''\n",
+	} {
+		if !strings.Contains(salida.String(), esperada) {
+			t.Errorf("no se encuentra %q en la salida de la simulación:\n%v", esperada, salida.String())
+		}
+	}
+}
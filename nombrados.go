@@ -0,0 +1,201 @@
+package bdsql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// resolverNombrados reemplaza los parámetros nombrados (':nombre') de la
+// condición por parámetros posicionales ('?'), ordenando los valores según
+// su aparición en la condición. No se admite el prefijo '@': en mysql y
+// mariadb '@nombre' es una variable de sesión y se conserva sin cambios.
+// Los valores de los parámetros nombrados pueden recibirse como:
+//   - un mapa con claves del tipo string (map[string]interface{}).
+//   - una estructura (o puntero de estructura): los nombres se obtienen de
+//     la etiqueta "bdsql" con las mismas reglas que en Resultado().
+//   - uno o más valores sql.Named().
+//
+// Si los valores no son de ninguno de estos tipos, la condición no contiene
+// parámetros nombrados y se devuelve sin cambios. Una estructura solo se
+// considera como valores nombrados si la condición contiene parámetros
+// nombrados: en caso contrario es el valor de un parámetro posicional.
+func resolverNombrados(condicion string, valores []interface{}) (string, []interface{}, error) {
+	nombrados, verificarSinUtilizar, ok := valoresNombrados(valores)
+	if !ok {
+		return condicion, valores, nil
+	}
+	var parametros = parametrosNombradosSQL(condicion)
+	if len(parametros) == 0 && !verificarSinUtilizar {
+		return condicion, valores, nil
+	}
+
+	var sb strings.Builder
+	var resultado []interface{}
+	var utilizados = make(map[string]bool)
+	var faltantes []string
+	var desde int
+	for _, p := range parametros {
+		var nombre = condicion[p.inicio+1 : p.fin]
+		valor, ok := nombrados[nombre]
+		if !ok {
+			faltantes = append(faltantes, nombre)
+			continue
+		}
+		utilizados[nombre] = true
+
+		sb.WriteString(condicion[desde:p.inicio])
+		sb.WriteByte('?')
+		desde = p.fin
+		resultado = append(resultado, valor)
+	}
+	sb.WriteString(condicion[desde:])
+
+	if len(faltantes) > 0 {
+		return "", nil, errorNuevo().asignarMotivoParametrosNombradosFaltantes(strings.Join(faltantes, ", "))
+	}
+	if verificarSinUtilizar {
+		var sinUtilizar []string
+		for nombre := range nombrados {
+			if !utilizados[nombre] {
+				sinUtilizar = append(sinUtilizar, nombre)
+			}
+		}
+		if len(sinUtilizar) > 0 {
+			sort.Strings(sinUtilizar)
+			return "", nil, errorNuevo().asignarMotivoParametrosNombradosSinUtilizar(strings.Join(sinUtilizar, ", "))
+		}
+	}
+
+	return sb.String(), resultado, nil
+}
+
+// valoresNombrados obtiene los valores de los parámetros nombrados.
+// Devuelve además si deben informarse los nombres que no fueron utilizados
+// (no se informan los campos de una estructura) y un valor lógico que
+// confirma que los valores recibidos son valores nombrados.
+func valoresNombrados(valores []interface{}) (map[string]interface{}, bool, bool) {
+	if len(valores) == 0 {
+		return nil, false, false
+	}
+
+	// valores sql.Named()
+	if _, ok := valores[0].(sql.NamedArg); ok {
+		var nombrados = make(map[string]interface{}, len(valores))
+		for _, v := range valores {
+			na, ok := v.(sql.NamedArg)
+			if !ok {
+				return nil, false, false
+			}
+			nombrados[na.Name] = na.Value
+		}
+		return nombrados, true, true
+	}
+
+	if len(valores) != 1 || valores[0] == nil {
+		return nil, false, false
+	}
+
+	var v = reflect.ValueOf(valores[0])
+	switch {
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		// mapa de valores
+		var nombrados = make(map[string]interface{}, v.Len())
+		for _, k := range v.MapKeys() {
+			nombrados[k.String()] = v.MapIndex(k).Interface()
+		}
+		return nombrados, true, true
+
+	case esEstructuraNombrada(v):
+		// estructura con etiquetas "bdsql"
+		if v.Kind() == reflect.Ptr {
+			v = v.Elem()
+		}
+		var nombrados = make(map[string]interface{})
+		for campoTabla, campoEstructura := range relacionDeCampos(v.Type()) {
+			nombrados[campoTabla] = v.FieldByName(campoEstructura).Interface()
+		}
+		return nombrados, false, true
+	}
+
+	return nil, false, false
+}
+
+// esEstructuraNombrada informa si el valor es una estructura (o puntero de
+// estructura) cuyos campos son los valores de los parámetros nombrados.
+// No se consideran las fechas, las expresiones ni los tipos que implementan
+// driver.Valuer.
+func esEstructuraNombrada(v reflect.Value) bool {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return false
+	}
+
+	switch v.Interface().(type) {
+	case time.Time, expresion, driver.Valuer:
+		return false
+	}
+	if _, ok := reflect.PtrTo(v.Type()).MethodByName("Value"); ok {
+		// tipos como sql.NullString implementan driver.Valuer
+		return false
+	}
+
+	return true
+}
+
+// parametroNombrado representa la ubicación de un parámetro nombrado dentro
+// de la sentencia: inicio es la posición del prefijo (':') y fin es la
+// posición siguiente al último caracter del nombre.
+type parametroNombrado struct {
+	inicio, fin int
+}
+
+// parametrosNombradosSQL devuelve las ubicaciones de los parámetros nombrados
+// (':nombre') de la sentencia, ignorando los que se encuentran dentro de
+// cadenas de texto, identificadores entre comillas y comentarios.
+// No se consideran los operadores '::' y ':=' ni las variables de sesión y de
+// sistema ('@nombre', '@@nombre').
+func parametrosNombradosSQL(sentencia string) []parametroNombrado {
+	var parametros []parametroNombrado
+	recorrerSQL(sentencia, func(i int) int {
+		var c = sentencia[i]
+		if c != ':' {
+			return i
+		}
+		if i > 0 && sentencia[i-1] == ':' {
+			return i
+		}
+
+		var fin = i + 1
+		for fin < len(sentencia) && esCaracterDeNombre(sentencia[fin], fin == i+1) {
+			fin++
+		}
+		if fin == i+1 {
+			return i
+		}
+		parametros = append(parametros, parametroNombrado{inicio: i, fin: fin})
+
+		return fin - 1
+	})
+
+	return parametros
+}
+
+func esCaracterDeNombre(c byte, primero bool) bool {
+	switch {
+	case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return true
+	case c >= '0' && c <= '9':
+		return !primero
+	}
+
+	return false
+}
@@ -0,0 +1,323 @@
+package bdsql
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Paginar ejecuta la sentencia obteniendo los registros de la página
+// recibida (la primera página es 1) y la cantidad total de registros de la
+// sentencia, utilizando las mismas tablas, juntas y condiciones.
+// Devuelve la cantidad de registros de la página y la cantidad total. El
+// tamaño de la página debe ser mayor a cero.
+//
+//	cant, total, err := bd.
+//		Seleccionar("personasPaginar").
+//		Tabla("personas").
+//		Campos("id", "apellidos").
+//		OrdenarPor("apellidos", "id").
+//		Resultado(&personas).
+//		Paginar(3, 50)
+func (o *seleccionar) Paginar(pagina, tamaño int) (int, int64, error) {
+	if tamaño < 1 {
+		return 0, 0, errorNuevo().asignarMotivoPaginaTamañoIncorrecto(tamaño)
+	}
+	if pagina < 1 {
+		pagina = 1
+	}
+	o.limite = tamaño
+	o.salto = (pagina - 1) * tamaño
+
+	cant, err := o.Ejecutar()
+	if err != nil {
+		return 0, 0, err
+	}
+	// la primera página incompleta contiene todos los registros
+	if pagina == 1 && cant < tamaño {
+		return cant, int64(cant), nil
+	}
+
+	total, err := o.Contar()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return cant, total, nil
+}
+
+// DespuesDe establece la paginación por cursor (keyset): la sentencia obtiene
+// los registros que se encuentran a continuación del cursor, según el
+// ordenamiento de la sentencia (OrdenarPor), en lugar de saltar registros
+// con 'offset'. El cursor vacío obtiene la primera página.
+// El cursor de la página siguiente se obtiene con SiguienteCursor() luego de
+// ejecutar la sentencia. El ordenamiento debe ser único (incluir la clave
+// primaria como último campo) y sus campos no deben contener nulos.
+//
+//	sel := bd.
+//		Seleccionar("auditoriaPaginar").
+//		Tabla("auditoria").
+//		Campos("id", "fecha", "detalle").
+//		OrdenarPor("fecha desc", "id desc").
+//		Limitar(100).
+//		DespuesDe(cursor).
+//		Resultado(&registros)
+//	_, err := sel.Ejecutar()
+//	siguiente, err := sel.SiguienteCursor()
+func (o *seleccionar) DespuesDe(cursor string) *seleccionar {
+	o.cursor = cursor
+
+	return o
+}
+
+// SiguienteCursor devuelve el cursor de la página siguiente a partir del
+// último registro obtenido en el resultado (Resultado). Devuelve un cursor
+// vacío cuando no existen más páginas.
+// El cursor es un texto opaco firmado: no puede ser alterado por el cliente.
+func (o *seleccionar) SiguienteCursor() (string, error) {
+	if len(o.ordenadoPor) == 0 {
+		return "", errorNuevo().asignarMotivoCursorSinOrdenamiento()
+	}
+	if o.objeto == nil {
+		return "", errorNuevo().asignarMotivoSeleccionarPunteroDeSlice()
+	}
+	var lista = reflect.ValueOf(o.objeto)
+	if lista.Kind() != reflect.Ptr || lista.Elem().Kind() != reflect.Slice {
+		return "", errorNuevo().asignarMotivoSeleccionarPunteroDeSlice()
+	}
+	lista = lista.Elem()
+	if lista.Len() == 0 || (o.limite > 0 && lista.Len() < o.limite) {
+		return "", nil
+	}
+
+	var ultimo = lista.Index(lista.Len() - 1)
+	var relacion = relacionDeCampos(ultimo.Type())
+	var valores []interface{}
+	for _, orden := range o.ordenadoPor {
+		campo, _ := campoDeOrden(orden)
+		if i := strings.LastIndex(campo, "."); i >= 0 {
+			campo = campo[i+1:]
+		}
+		nombre, ok := relacion[campo]
+		if !ok {
+			return "", errorNuevo().asignarMotivoSeleccionarCamposFaltantes(campo)
+		}
+		valores = append(valores, ultimo.FieldByName(nombre).Interface())
+	}
+
+	return o.bd.codificarCursor(valores)
+}
+
+// AsignarClaveDeCursores establece la clave con la cual se firman los
+// cursores de paginación. Si no se asigna, se genera una clave aleatoria al
+// crear el primer cursor; en ese caso los cursores solo son válidos para la
+// instancia que los generó.
+func (bd *BD) AsignarClaveDeCursores(clave []byte) *BD {
+	bd.mux.Lock()
+	bd.claveCursores = clave
+	bd.mux.Unlock()
+
+	return bd
+}
+
+// obtenerClaveCursores devuelve la clave de los cursores de paginación.
+func (bd *BD) obtenerClaveCursores() ([]byte, error) {
+	bd.mux.Lock()
+	defer bd.mux.Unlock()
+
+	if len(bd.claveCursores) == 0 {
+		var clave = make([]byte, 32)
+		if _, err := rand.Read(clave); err != nil {
+			return nil, errorNuevo().asignarOrigen(err).asignarMotivoCursorSinClave()
+		}
+		bd.claveCursores = clave
+	}
+
+	return bd.claveCursores, nil
+}
+
+// codificarCursor devuelve el cursor firmado que contiene los valores
+// recibidos. Cada valor se codifica junto con su tipo, de manera que al
+// decodificarlo se obtenga el mismo tipo de valor.
+func (bd *BD) codificarCursor(valores []interface{}) (string, error) {
+	var textos = make([]string, len(valores))
+	for i, valor := range valores {
+		texto, ok := codificarValorCursor(valor)
+		if !ok {
+			return "", errorNuevo().asignarMotivoSeleccionarTipoDeCampoIncorrecto()
+		}
+		textos[i] = texto
+	}
+
+	datos, err := json.Marshal(textos)
+	if err != nil {
+		return "", errorNuevo().asignarOrigen(err).asignarMotivoCursorInvalido()
+	}
+
+	firma, err := bd.firmarCursor(datos)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(datos) + "." + base64.RawURLEncoding.EncodeToString(firma), nil
+}
+
+// decodificarCursor verifica la firma del cursor y devuelve sus valores.
+func (bd *BD) decodificarCursor(cursor string, cantidad int) ([]interface{}, error) {
+	var partes = strings.Split(cursor, ".")
+	if len(partes) != 2 {
+		return nil, errorNuevo().asignarMotivoCursorInvalido()
+	}
+	datos, err := base64.RawURLEncoding.DecodeString(partes[0])
+	if err != nil {
+		return nil, errorNuevo().asignarOrigen(err).asignarMotivoCursorInvalido()
+	}
+	firma, err := base64.RawURLEncoding.DecodeString(partes[1])
+	if err != nil {
+		return nil, errorNuevo().asignarOrigen(err).asignarMotivoCursorInvalido()
+	}
+	esperada, err := bd.firmarCursor(datos)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(firma, esperada) {
+		return nil, errorNuevo().asignarMotivoCursorInvalido()
+	}
+
+	var textos []string
+	if err := json.Unmarshal(datos, &textos); err != nil {
+		return nil, errorNuevo().asignarOrigen(err).asignarMotivoCursorInvalido()
+	}
+	if len(textos) != cantidad {
+		return nil, errorNuevo().asignarMotivoCursorInvalido()
+	}
+	var valores = make([]interface{}, len(textos))
+	for i, texto := range textos {
+		valor, ok := decodificarValorCursor(texto)
+		if !ok {
+			return nil, errorNuevo().asignarMotivoCursorInvalido()
+		}
+		valores[i] = valor
+	}
+
+	return valores, nil
+}
+
+func (bd *BD) firmarCursor(datos []byte) ([]byte, error) {
+	clave, err := bd.obtenerClaveCursores()
+	if err != nil {
+		return nil, err
+	}
+	var mac = hmac.New(sha256.New, clave)
+	mac.Write(datos)
+
+	return mac.Sum(nil), nil
+}
+
+// codificarValorCursor devuelve el texto 'tipo:valor' del valor recibido.
+func codificarValorCursor(valor interface{}) (string, bool) {
+	if valor == nil {
+		return "n:", true
+	}
+	if fecha, ok := valor.(time.Time); ok {
+		return "t:" + fecha.Format(time.RFC3339Nano), true
+	}
+
+	var v = reflect.ValueOf(valor)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "i:" + strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "u:" + strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return "f:" + strconv.FormatFloat(v.Float(), 'g', -1, 64), true
+	case reflect.Bool:
+		return "b:" + strconv.FormatBool(v.Bool()), true
+	case reflect.String:
+		return "s:" + v.String(), true
+	}
+
+	return "", false
+}
+
+// decodificarValorCursor devuelve el valor de un texto 'tipo:valor'.
+func decodificarValorCursor(texto string) (interface{}, bool) {
+	if len(texto) < 2 || texto[1] != ':' {
+		return nil, false
+	}
+
+	var valor interface{}
+	var err error
+	switch dato := texto[2:]; texto[0] {
+	case 'n':
+		return nil, true
+	case 's':
+		return dato, true
+	case 'i':
+		valor, err = strconv.ParseInt(dato, 10, 64)
+	case 'u':
+		valor, err = strconv.ParseUint(dato, 10, 64)
+	case 'f':
+		valor, err = strconv.ParseFloat(dato, 64)
+	case 'b':
+		valor, err = strconv.ParseBool(dato)
+	case 't':
+		valor, err = time.Parse(time.RFC3339Nano, dato)
+	default:
+		return nil, false
+	}
+
+	return valor, err == nil
+}
+
+// condicionCursor devuelve la condición que obtiene los registros que se
+// encuentran a continuación de los valores del cursor, según el
+// ordenamiento: 'a < ? or (a = ? and id < ?)' para 'order by a desc, id desc'.
+func condicionCursor(ordenadoPor []string, valores []interface{}) (string, []interface{}) {
+	var partes []string
+	var resultado []interface{}
+	for i := range ordenadoPor {
+		var terminos []string
+		for j := 0; j < i; j++ {
+			campo, _ := campoDeOrden(ordenadoPor[j])
+			terminos = append(terminos, campo+" = ?")
+			resultado = append(resultado, valores[j])
+		}
+		campo, descendente := campoDeOrden(ordenadoPor[i])
+		var operador = ">"
+		if descendente {
+			operador = "<"
+		}
+		terminos = append(terminos, fmt.Sprintf("%v %v ?", campo, operador))
+		resultado = append(resultado, valores[i])
+
+		if len(terminos) > 1 {
+			partes = append(partes, "("+strings.Join(terminos, " and ")+")")
+		} else {
+			partes = append(partes, terminos[0])
+		}
+	}
+	if len(partes) == 1 {
+		return partes[0], resultado
+	}
+
+	return "(" + strings.Join(partes, " or ") + ")", resultado
+}
+
+// campoDeOrden devuelve el campo de un elemento del ordenamiento y si el
+// orden es descendente: "fecha desc" devuelve "fecha" y verdadero.
+func campoDeOrden(orden string) (string, bool) {
+	var partes = strings.Fields(orden)
+	if len(partes) == 0 {
+		return "", false
+	}
+
+	return partes[0], len(partes) > 1 && strings.EqualFold(partes[len(partes)-1], "desc")
+}
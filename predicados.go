@@ -0,0 +1,341 @@
+package bdsql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Predicado representa una condición SQL construida a partir de funciones
+// (Igual, Mayor, Entre, Y, O, etc.). Puede utilizarse en Condicion() y
+// Teniendo() de todas las sentencias y en las condiciones de las juntas.
+//
+// Los predicados marcados como opcionales (Opcional()) se omiten cuando su
+// valor es nulo o el valor cero de su tipo, lo cual simplifica la
+// construcción de filtros a partir de campos de búsqueda opcionales:
+//
+//	cant, err := bd.
+//		Seleccionar("personasBuscar").
+//		Tabla("personas").
+//		Campos("*").
+//		Condicion(bdsql.Y(
+//			bdsql.Igual("estado", filtro.Estado).Opcional(),
+//			bdsql.Como("apellidos", filtro.Apellidos+"%").Opcional(),
+//			bdsql.Entre("alta", filtro.Desde, filtro.Hasta).Opcional(),
+//		)).
+//		Resultado(&personas).
+//		Ejecutar()
+type Predicado interface {
+	// Opcional establece que el predicado se omita cuando su valor es nulo
+	// o el valor cero de su tipo.
+	Opcional() Predicado
+
+	// generarPredicado devuelve el texto del predicado y sus valores. Un
+	// texto vacío indica que el predicado se omite.
+	generarPredicado() (string, []interface{})
+}
+
+// Igual crea el predicado 'campo = valor'.
+func Igual(campo string, valor interface{}) Predicado {
+	return comparacion{campo: campo, operador: "=", valor: valor}
+}
+
+// Diferente crea el predicado 'campo <> valor'.
+func Diferente(campo string, valor interface{}) Predicado {
+	return comparacion{campo: campo, operador: "<>", valor: valor}
+}
+
+// Mayor crea el predicado 'campo > valor'.
+func Mayor(campo string, valor interface{}) Predicado {
+	return comparacion{campo: campo, operador: ">", valor: valor}
+}
+
+// MayorOIgual crea el predicado 'campo >= valor'.
+func MayorOIgual(campo string, valor interface{}) Predicado {
+	return comparacion{campo: campo, operador: ">=", valor: valor}
+}
+
+// Menor crea el predicado 'campo < valor'.
+func Menor(campo string, valor interface{}) Predicado {
+	return comparacion{campo: campo, operador: "<", valor: valor}
+}
+
+// MenorOIgual crea el predicado 'campo <= valor'.
+func MenorOIgual(campo string, valor interface{}) Predicado {
+	return comparacion{campo: campo, operador: "<=", valor: valor}
+}
+
+// Como crea el predicado 'campo like patron'.
+func Como(campo string, patron interface{}) Predicado {
+	return comparacion{campo: campo, operador: "like", valor: patron}
+}
+
+// En crea el predicado 'campo in (valores)'. Recibe una lista (slice) de
+// valores; si la lista está vacía el predicado resulta falso (o se omite si
+// es opcional).
+func En(campo string, valores interface{}) Predicado {
+	return comparacion{campo: campo, operador: "in", valor: valores}
+}
+
+// Entre crea el predicado 'campo between desde and hasta'. Si es opcional y
+// solo uno de los extremos tiene valor, se convierte en 'campo >= desde' o
+// 'campo <= hasta'.
+func Entre(campo string, desde, hasta interface{}) Predicado {
+	return entre{campo: campo, desde: desde, hasta: hasta}
+}
+
+// EsNulo crea el predicado 'campo is null'.
+func EsNulo(campo string) Predicado {
+	return nulo{campo: campo}
+}
+
+// NoEsNulo crea el predicado 'campo is not null'.
+func NoEsNulo(campo string) Predicado {
+	return nulo{campo: campo, negado: true}
+}
+
+// Existe crea el predicado 'exists (subconsulta)'.
+func Existe(subconsulta *seleccionar) Predicado {
+	return existe{subconsulta: subconsulta}
+}
+
+// NoExiste crea el predicado 'not exists (subconsulta)'.
+func NoExiste(subconsulta *seleccionar) Predicado {
+	return existe{subconsulta: subconsulta, negado: true}
+}
+
+// Crudo crea un predicado a partir de un texto SQL con sus valores, para ser
+// combinado con otros predicados.
+func Crudo(condicion string, valores ...interface{}) Predicado {
+	return crudo{condicion: condicion, valores: valores}
+}
+
+// Y crea un predicado que se cumple cuando todos los predicados recibidos se
+// cumplen. Los predicados nulos u omitidos no se tienen en cuenta.
+func Y(predicados ...Predicado) Predicado {
+	return grupo{operador: "and", predicados: predicados}
+}
+
+// O crea un predicado que se cumple cuando al menos uno de los predicados
+// recibidos se cumple. Los predicados nulos u omitidos no se tienen en cuenta.
+func O(predicados ...Predicado) Predicado {
+	return grupo{operador: "or", predicados: predicados}
+}
+
+// No crea un predicado que niega al predicado recibido.
+func No(predicado Predicado) Predicado {
+	return negacion{predicado: predicado}
+}
+
+// -----------------------------------------------------------------------------
+
+type comparacion struct {
+	campo    string
+	operador string
+	valor    interface{}
+	opcional bool
+}
+
+func (p comparacion) Opcional() Predicado {
+	p.opcional = true
+	return p
+}
+
+func (p comparacion) generarPredicado() (string, []interface{}) {
+	if p.opcional && esValorVacio(p.valor) {
+		return "", nil
+	}
+	if p.operador == "in" {
+		return fmt.Sprintf("%v in (?)", p.campo), []interface{}{p.valor}
+	}
+
+	return fmt.Sprintf("%v %v ?", p.campo, p.operador), []interface{}{p.valor}
+}
+
+type entre struct {
+	campo    string
+	desde    interface{}
+	hasta    interface{}
+	opcional bool
+}
+
+func (p entre) Opcional() Predicado {
+	p.opcional = true
+	return p
+}
+
+func (p entre) generarPredicado() (string, []interface{}) {
+	if p.opcional {
+		switch desdeVacio, hastaVacio := esValorVacio(p.desde), esValorVacio(p.hasta); {
+		case desdeVacio && hastaVacio:
+			return "", nil
+		case desdeVacio:
+			return fmt.Sprintf("%v <= ?", p.campo), []interface{}{p.hasta}
+		case hastaVacio:
+			return fmt.Sprintf("%v >= ?", p.campo), []interface{}{p.desde}
+		}
+	}
+
+	return fmt.Sprintf("%v between ? and ?", p.campo), []interface{}{p.desde, p.hasta}
+}
+
+type nulo struct {
+	campo  string
+	negado bool
+}
+
+func (p nulo) Opcional() Predicado {
+	// no tiene valor: nunca se omite
+	return p
+}
+
+func (p nulo) generarPredicado() (string, []interface{}) {
+	if p.negado {
+		return fmt.Sprintf("%v is not null", p.campo), nil
+	}
+
+	return fmt.Sprintf("%v is null", p.campo), nil
+}
+
+type existe struct {
+	subconsulta *seleccionar
+	negado      bool
+}
+
+func (p existe) Opcional() Predicado {
+	// no tiene valor: nunca se omite
+	return p
+}
+
+func (p existe) generarPredicado() (string, []interface{}) {
+	if p.negado {
+		return "not exists (?)", []interface{}{p.subconsulta}
+	}
+
+	return "exists (?)", []interface{}{p.subconsulta}
+}
+
+type crudo struct {
+	condicion string
+	valores   []interface{}
+	opcional  bool
+}
+
+func (p crudo) Opcional() Predicado {
+	p.opcional = true
+	return p
+}
+
+func (p crudo) generarPredicado() (string, []interface{}) {
+	if p.opcional {
+		for _, v := range p.valores {
+			if esValorVacio(v) {
+				return "", nil
+			}
+		}
+	}
+
+	return "(" + p.condicion + ")", p.valores
+}
+
+type grupo struct {
+	operador   string
+	predicados []Predicado
+}
+
+func (p grupo) Opcional() Predicado {
+	// un grupo sin predicados se omite siempre
+	return p
+}
+
+func (p grupo) generarPredicado() (string, []interface{}) {
+	var partes []string
+	var valores []interface{}
+	for _, predicado := range p.predicados {
+		if predicado == nil {
+			continue
+		}
+		s, vs := predicado.generarPredicado()
+		if s == "" {
+			continue
+		}
+		partes = append(partes, s)
+		valores = append(valores, vs...)
+	}
+
+	switch len(partes) {
+	case 0:
+		return "", nil
+	case 1:
+		return partes[0], valores
+	}
+
+	return "(" + strings.Join(partes, " "+p.operador+" ") + ")", valores
+}
+
+type negacion struct {
+	predicado Predicado
+}
+
+func (p negacion) Opcional() Predicado {
+	if p.predicado != nil {
+		p.predicado = p.predicado.Opcional()
+	}
+	return p
+}
+
+func (p negacion) generarPredicado() (string, []interface{}) {
+	if p.predicado == nil {
+		return "", nil
+	}
+	s, valores := p.predicado.generarPredicado()
+	if s == "" {
+		return "", nil
+	}
+
+	return fmt.Sprintf("not (%v)", s), valores
+}
+
+// -----------------------------------------------------------------------------
+
+// esValorVacio informa si el valor es nulo o el valor cero de su tipo.
+// Las listas (slices) y mapas se consideran vacíos cuando no tienen elementos.
+func esValorVacio(valor interface{}) bool {
+	if valor == nil {
+		return true
+	}
+
+	var v = reflect.ValueOf(valor)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+
+	return v.IsZero()
+}
+
+// resolverCondicion obtiene el texto y los valores de una condición, la cual
+// puede ser un texto SQL (con parámetros posicionales o nombrados) o un
+// predicado. Los predicados se incorporan en la sentencia como un parámetro
+// que se expande en cada ejecución, de manera que la sentencia almacenada
+// por su nombre no depende de los predicados omitidos.
+// Si no se permite una condición vacía, un predicado completamente omitido
+// devuelve un error.
+func resolverCondicion(condicion interface{}, valores []interface{}, permitirVacia bool) (string, []interface{}, error) {
+	switch c := condicion.(type) {
+	case string:
+		return resolverNombrados(c, valores)
+	case Predicado:
+		if len(valores) > 0 {
+			return "", nil, errorNuevo().asignarMotivoCondicionIncorrecta()
+		}
+		if s, _ := c.generarPredicado(); s == "" && !permitirVacia {
+			return "", nil, errorNuevo().asignarMotivoCondicionVacia()
+		}
+		return "?", []interface{}{c}, nil
+	}
+
+	return "", nil, errorNuevo().asignarMotivoCondicionIncorrecta()
+}
@@ -0,0 +1,372 @@
+package bdsql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Repositorio contiene las operaciones habituales sobre una tabla cuyos
+// registros se representan con la estructura T. Los campos de T se
+// relacionan con las columnas de la tabla con las mismas reglas que al
+// obtener los registros de un 'select'; la etiqueta "bdsql" admite además
+// las opciones:
+//
+//	clave:   el campo forma parte de la clave primaria (por defecto, la
+//	         columna 'id')
+//	version: el campo mantiene la versión del registro (bloqueo optimista)
+//
+// Los nombres de las sentencias se derivan del tipo T, de sus columnas, de
+// la tabla y de la operación.
+//
+//	type Persona struct {
+//		ID        int64  `bdsql:"id,clave"`
+//		Apellidos string `bdsql:"apellidos"`
+//		Version   int    `bdsql:"version,version"`
+//	}
+//	repo := bdsql.NuevoRepositorio[Persona](bd, "personas")
+//	persona, existe, err := repo.ObtenerPorID(10)
+type Repositorio[T any] struct {
+	ej    Ejecutor
+	tabla string
+
+	nombre   string             // prefijo de los nombres de las sentencias
+	campos   []campoRepositorio // campos de la estructura relacionados con columnas
+	claves   []campoRepositorio // campos de la clave primaria
+	version  *campoRepositorio  // campo de versión (bloqueo optimista)
+	columnas []string           // columnas de todos los campos
+
+	err error // error producido al analizar la estructura
+}
+
+// campoRepositorio relaciona un campo de la estructura con una columna.
+type campoRepositorio struct {
+	indice  int    // posición del campo en la estructura
+	columna string // nombre de la columna de la tabla
+}
+
+// NuevoRepositorio crea el repositorio de la tabla para la estructura T.
+// Recibe un Ejecutor (una base de datos o una transacción); las operaciones
+// se ejecutan sobre él. Si T no es una estructura válida, las operaciones del
+// repositorio devuelven el error.
+func NuevoRepositorio[T any](ej Ejecutor, tabla string) *Repositorio[T] {
+	var r = &Repositorio[T]{ej: ej, tabla: tabla}
+
+	var tipo = reflect.TypeOf((*T)(nil)).Elem()
+	if tipo.Kind() != reflect.Struct {
+		r.err = errorNuevo().asignarMotivoRepositorioIncorrecto(fmt.Sprintf("%v no es una estructura", tipo))
+		return r
+	}
+	var id = -1
+	for i := 0; i < tipo.NumField(); i++ {
+		var campo = tipo.Field(i)
+		if campo.PkgPath != "" {
+			// campo no exportado
+			continue
+		}
+		columna, opciones := etiquetaBdsql(campo)
+		switch columna {
+		case "-":
+			continue
+		case "":
+			columna = strings.ToLower(campo.Name)
+		}
+
+		var c = campoRepositorio{indice: i, columna: columna}
+		for _, opcion := range opciones {
+			switch opcion {
+			case "clave":
+				r.claves = append(r.claves, c)
+			case "version":
+				r.version = &c
+			}
+		}
+		if columna == "id" {
+			id = len(r.campos)
+		}
+		r.campos = append(r.campos, c)
+		r.columnas = append(r.columnas, columna)
+	}
+	if len(r.claves) == 0 && id >= 0 {
+		r.claves = append(r.claves, r.campos[id])
+	}
+	r.nombre = r.obtenerNombre(tipo)
+
+	return r
+}
+
+// obtenerNombre devuelve el prefijo de los nombres de las sentencias del
+// repositorio. Los tipos anónimos o locales (declarados en funciones) pueden
+// compartir el paquete y el nombre: se incorporan las columnas, las claves y
+// el campo de versión para que los repositorios de la misma tabla solo
+// compartan las sentencias cuando estas son iguales.
+func (r *Repositorio[T]) obtenerNombre(tipo reflect.Type) string {
+	var claves []string
+	for _, c := range r.claves {
+		claves = append(claves, c.columna)
+	}
+	var version string
+	if r.version != nil {
+		version = r.version.columna
+	}
+
+	return fmt.Sprintf("repositorio.%v.%v.%v(%v|%v|%v)", tipo.PkgPath(), tipo.String(), r.tabla,
+		strings.Join(r.columnas, ","), strings.Join(claves, ","), version)
+}
+
+// ObtenerPorID obtiene el registro por los valores de su clave primaria. El
+// valor lógico informa si el registro existe.
+func (r *Repositorio[T]) ObtenerPorID(clave ...interface{}) (T, bool, error) {
+	var vacio T
+	condicion, err := r.condicionClave(clave)
+	if err != nil {
+		return vacio, false, err
+	}
+
+	var registros []T
+	_, err = r.ej.
+		Seleccionar(r.nombre+".obtenerPorID").
+		Tabla(r.tabla).
+		Campos(r.columnas...).
+		Condicion(condicion, clave...).
+		Resultado(&registros).
+		Ejecutar()
+	if err != nil || len(registros) == 0 {
+		return vacio, false, err
+	}
+
+	return registros[0], true, nil
+}
+
+// Buscar obtiene los registros que cumplen la condición (texto con sus
+// valores o predicado), ordenados por la clave primaria. Una condición nula
+// o vacía obtiene todos los registros.
+func (r *Repositorio[T]) Buscar(condicion interface{}, valores ...interface{}) ([]T, error) {
+	var registros []T
+	sel, err := r.seleccionar(condicion, valores)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := sel.Resultado(&registros).Ejecutar(); err != nil {
+		return nil, err
+	}
+
+	return registros, nil
+}
+
+// Contar obtiene la cantidad de registros que cumplen la condición.
+func (r *Repositorio[T]) Contar(condicion interface{}, valores ...interface{}) (int64, error) {
+	sel, err := r.seleccionar(condicion, valores)
+	if err != nil {
+		return 0, err
+	}
+
+	return sel.Contar()
+}
+
+// Paginar obtiene los registros de la página recibida (la primera página es
+// 1) que cumplen la condición, ordenados por la clave primaria, y la
+// cantidad total de registros que la cumplen.
+func (r *Repositorio[T]) Paginar(pagina, tamaño int, condicion interface{}, valores ...interface{}) ([]T, int64, error) {
+	var registros []T
+	sel, err := r.seleccionar(condicion, valores)
+	if err != nil {
+		return nil, 0, err
+	}
+	_, total, err := sel.Resultado(&registros).Paginar(pagina, tamaño)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return registros, total, nil
+}
+
+// Insertar inserta el registro. Si la clave primaria es un único campo
+// entero con valor cero, la columna se omite (autoincremental) y se asigna
+// en el campo el id generado.
+func (r *Repositorio[T]) Insertar(registro *T) error {
+	if r.err != nil {
+		return r.err
+	}
+	if registro == nil {
+		return errorNuevo().asignarMotivoRepositorioIncorrecto("el registro es nulo")
+	}
+
+	var v = reflect.ValueOf(registro).Elem()
+	var autoIncremental = len(r.claves) == 1 && esEntero(v.Field(r.claves[0].indice)) && v.Field(r.claves[0].indice).IsZero()
+
+	var nombre = r.nombre + ".insertar"
+	var campos []string
+	var valores []interface{}
+	for _, c := range r.campos {
+		if autoIncremental && c.indice == r.claves[0].indice {
+			continue
+		}
+		campos = append(campos, c.columna)
+		valores = append(valores, v.Field(c.indice).Interface())
+	}
+	if !autoIncremental {
+		nombre += "ConClave"
+	}
+
+	var sen = r.ej.Insertar(nombre).Tabla(r.tabla).Campos(campos...).Valores(valores...)
+	if !autoIncremental {
+		return sen.Ejecutar()
+	}
+
+	var id int64
+	if err := sen.ObtenerID(&id).Ejecutar(); err != nil {
+		return err
+	}
+	asignarEntero(v.Field(r.claves[0].indice), id)
+
+	return nil
+}
+
+// Modificar modifica el registro identificado por su clave primaria. Si la
+// estructura tiene un campo de versión, se aplica el bloqueo optimista (ver
+// ConVersion) y se incrementa la versión del registro recibido.
+func (r *Repositorio[T]) Modificar(registro *T) error {
+	if r.err != nil {
+		return r.err
+	}
+	if registro == nil {
+		return errorNuevo().asignarMotivoRepositorioIncorrecto("el registro es nulo")
+	}
+
+	var v = reflect.ValueOf(registro).Elem()
+	var clave = make([]interface{}, len(r.claves))
+	for i, c := range r.claves {
+		clave[i] = v.Field(c.indice).Interface()
+	}
+	condicion, err := r.condicionClave(clave)
+	if err != nil {
+		return err
+	}
+
+	var campos []string
+	var valores []interface{}
+	for _, c := range r.campos {
+		if r.esClave(c) || (r.version != nil && c.indice == r.version.indice) {
+			continue
+		}
+		campos = append(campos, c.columna)
+		valores = append(valores, v.Field(c.indice).Interface())
+	}
+
+	var sen = r.ej.
+		Modificar(r.nombre+".modificar").
+		Tabla(r.tabla).
+		Campos(campos...).
+		Valores(valores...).
+		Condicion(condicion, clave...)
+	if r.version == nil {
+		return sen.Ejecutar()
+	}
+
+	var version = v.Field(r.version.indice)
+	if err := sen.ConVersion(r.version.columna, version.Interface()).Ejecutar(); err != nil {
+		return err
+	}
+	if esEntero(version) {
+		asignarEntero(version, valorEntero(version)+1)
+	}
+
+	return nil
+}
+
+// Eliminar elimina el registro identificado por los valores de su clave
+// primaria.
+func (r *Repositorio[T]) Eliminar(clave ...interface{}) error {
+	condicion, err := r.condicionClave(clave)
+	if err != nil {
+		return err
+	}
+
+	return r.ej.
+		Eliminar(r.nombre+".eliminar").
+		Tabla(r.tabla).
+		Condicion(condicion, clave...).
+		Ejecutar()
+}
+
+// seleccionar devuelve la sentencia 'select' de los registros que cumplen
+// la condición, ordenados por la clave primaria.
+func (r *Repositorio[T]) seleccionar(condicion interface{}, valores []interface{}) (*seleccionar, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	// la condición varía en cada invocación: la sentencia no se nombra
+	var sel = r.ej.Seleccionar("-").Tabla(r.tabla).Campos(r.columnas...)
+	if condicion != nil && condicion != "" {
+		sel.Condicion(condicion, valores...)
+	}
+	var orden []string
+	for _, c := range r.claves {
+		orden = append(orden, c.columna)
+	}
+
+	return sel.OrdenarPor(orden...), nil
+}
+
+// condicionClave devuelve la condición de la clave primaria y verifica que
+// se reciba un valor por cada campo de la clave.
+func (r *Repositorio[T]) condicionClave(clave []interface{}) (string, error) {
+	if r.err != nil {
+		return "", r.err
+	}
+	if len(r.claves) == 0 {
+		return "", errorNuevo().asignarMotivoRepositorioIncorrecto("la estructura no tiene campos de clave primaria")
+	}
+	if len(clave) != len(r.claves) {
+		return "", errorNuevo().asignarMotivoCamposValoresDiferenteCantidad()
+	}
+
+	var condiciones = make([]string, len(r.claves))
+	for i, c := range r.claves {
+		condiciones[i] = c.columna + " = ?"
+	}
+
+	return strings.Join(condiciones, " and "), nil
+}
+
+// esClave informa si el campo forma parte de la clave primaria.
+func (r *Repositorio[T]) esClave(campo campoRepositorio) bool {
+	for _, c := range r.claves {
+		if c.indice == campo.indice {
+			return true
+		}
+	}
+
+	return false
+}
+
+// esEntero informa si el valor es de un tipo entero (con o sin signo).
+func esEntero(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+
+	return false
+}
+
+// valorEntero devuelve el valor de un entero (con o sin signo).
+func valorEntero(v reflect.Value) int64 {
+	if v.CanInt() {
+		return v.Int()
+	}
+
+	return int64(v.Uint())
+}
+
+// asignarEntero asigna el valor en un entero (con o sin signo).
+func asignarEntero(v reflect.Value, valor int64) {
+	if v.CanInt() {
+		v.SetInt(valor)
+		return
+	}
+	v.SetUint(uint64(valor))
+}
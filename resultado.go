@@ -0,0 +1,137 @@
+package bdsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Resultado representa el resultado de la ejecución de una sentencia de
+// escritura ('insert', 'update' o 'delete').
+type Resultado struct {
+	RegistrosAfectados int64    // cantidad de registros afectados por la sentencia
+	UltimoID           int64    // último id insertado (tablas con clave principal autoincremental)
+	Advertencias       []string // advertencias emitidas por el motor al ejecutar la sentencia
+}
+
+// ejecutarSentencia ejecuta una sentencia de escritura y obtiene su resultado.
+// Cuando se solicitan las advertencias, la sentencia y la consulta 'show
+// warnings' se ejecutan sobre la misma conexión (fuera de una transacción se
+// reserva una conexión del pool). En el modo simulación, la sentencia no se
+// ejecuta.
+func ejecutarSentencia(bd *BD, con Conexion, sentencia string, valores []interface{}, conAdvertencias bool) (Resultado, error) {
+	if bd.EnSimulacion() {
+		return bd.simularSentencia(sentencia, valores)
+	}
+
+	var ctx = context.Background()
+
+	if db, ok := con.(*sql.DB); ok && conAdvertencias {
+		// ejecución fuera de una transacción sobre una conexión reservada
+		c, err := db.Conn(ctx)
+		if err != nil {
+			return Resultado{}, errorNuevo().asignarOrigen(err).asignarMotivoConexionAbrir()
+		}
+		defer c.Close()
+		con = c
+	}
+
+	res, err := con.ExecContext(ctx, sentencia, valores...)
+	if err != nil {
+		return Resultado{}, resolverErrorMysql(err)
+	}
+
+	resultado, err := obtenerResultado(res)
+	if err != nil {
+		return resultado, err
+	}
+	if conAdvertencias {
+		if resultado.Advertencias, err = leerAdvertencias(con); err != nil {
+			return resultado, err
+		}
+	}
+
+	return resultado, nil
+}
+
+// prepararSentencia crea la sentencia preparada sobre la conexión. En el
+// modo simulación la sentencia no se prepara en el servidor (se devuelve
+// nil) y todas sus ejecuciones se simulan.
+func prepararSentencia(bd *BD, con Conexion, sentencia string) (*sql.Stmt, error) {
+	if bd.EnSimulacion() {
+		return nil, nil
+	}
+
+	stmt, err := con.PrepareContext(context.Background(), sentencia)
+	if err != nil {
+		return nil, errorNuevo().asignarOrigen(err).asignarMotivoSentenciaPreparadaCrear()
+	}
+
+	return stmt, nil
+}
+
+// ejecutarSentenciaPreparada ejecuta una sentencia preparada y obtiene su
+// resultado. Las advertencias solo pueden leerse cuando la sentencia
+// preparada pertenece a una transacción, dado que fuera de ella no es posible
+// garantizar que 'show warnings' se ejecute sobre la misma conexión. En el
+// modo simulación, o si la sentencia se creó en el modo simulación, la
+// sentencia no se ejecuta.
+func ejecutarSentenciaPreparada(bd *BD, stmt *sql.Stmt, sentencia string, con Conexion, valores []interface{}, conAdvertencias bool) (Resultado, error) {
+	if stmt == nil || bd.EnSimulacion() {
+		return bd.simularSentencia(sentencia, valores)
+	}
+
+	res, err := stmt.Exec(valores...)
+	if err != nil {
+		return Resultado{}, resolverErrorMysql(err)
+	}
+
+	resultado, err := obtenerResultado(res)
+	if err != nil {
+		return resultado, err
+	}
+	if conAdvertencias && enTransaccion(con) {
+		if resultado.Advertencias, err = leerAdvertencias(con); err != nil {
+			return resultado, err
+		}
+	}
+
+	return resultado, nil
+}
+
+func obtenerResultado(res sql.Result) (Resultado, error) {
+	var resultado Resultado
+	var err error
+
+	if resultado.RegistrosAfectados, err = res.RowsAffected(); err != nil {
+		return resultado, errorNuevo().asignarOrigen(err).asignarMotivoObtencionDeRegistrosAfectados()
+	}
+	if resultado.UltimoID, err = res.LastInsertId(); err != nil {
+		return resultado, errorNuevo().asignarOrigen(err).asignarMotivoObtencionDeID()
+	}
+
+	return resultado, nil
+}
+
+func leerAdvertencias(con Conexion) ([]string, error) {
+	filas, err := con.QueryContext(context.Background(), "show warnings;")
+	if err != nil {
+		return nil, resolverErrorMysql(err)
+	}
+	defer filas.Close()
+
+	var advertencias []string
+	for filas.Next() {
+		var nivel, mensaje string
+		var codigo int
+		if err := filas.Scan(&nivel, &codigo, &mensaje); err != nil {
+			return nil, errorNuevo().asignarOrigen(err).asignarMotivoSeleccionarLecturaDeCampos()
+		}
+		advertencias = append(advertencias, fmt.Sprintf("%v (%v): %v", nivel, codigo, mensaje))
+	}
+	if err := filas.Err(); err != nil {
+		return nil, resolverErrorMysql(err)
+	}
+
+	return advertencias, nil
+}
@@ -0,0 +1,87 @@
+package bdsql
+
+import (
+	"fmt"
+	"strings"
+)
+
+type crearIndice struct {
+	bd *BD
+
+	nombre     string
+	tabla      string
+	columnas   []string
+	unico      bool
+	siNoExiste bool
+}
+
+// Tabla establece la tabla del índice.
+func (o *crearIndice) Tabla(tabla string) *crearIndice {
+	o.tabla = tabla
+	return o
+}
+
+// Columnas establece las columnas del índice, en orden. Cada columna puede
+// incluir el largo del prefijo o el orden: "apellidos(10)", "alta desc".
+func (o *crearIndice) Columnas(columnas ...string) *crearIndice {
+	o.columnas = columnas
+	return o
+}
+
+// Unico establece que el índice sea único: 'create unique index'.
+func (o *crearIndice) Unico() *crearIndice {
+	o.unico = true
+	return o
+}
+
+// SiNoExiste establece que el índice se cree solo si no existe:
+// 'create index if not exists'. Solo es admitida por Mariadb.
+func (o *crearIndice) SiNoExiste() *crearIndice {
+	o.siNoExiste = true
+	return o
+}
+
+// SQL devuelve la sentencia SQL.
+func (o *crearIndice) SQL() (string, error) {
+	return o.generarSQL()
+}
+
+// Ejecutar ejecuta la sentencia SQL.
+func (o *crearIndice) Ejecutar() error {
+	var sentencia, err = o.generarSQL()
+	if err != nil {
+		return err
+	}
+	_, err = ejecutarSentencia(o.bd, o.bd.db, sentencia, nil, false)
+
+	return err
+}
+
+func (o *crearIndice) generarSQL() (string, error) {
+	var err = errorNuevo()
+	// verificar que el nombre de la tabla no se encuentre vacía
+	if o.tabla == "" {
+		err.asignarMotivoNombreDeTablaVacia()
+	}
+	// verificar que el índice tenga nombre y columnas
+	if o.nombre == "" || len(o.columnas) == 0 {
+		err.asignarMotivoNombresDeCamposVacios()
+	}
+	if o.siNoExiste && !o.bd.obtenerDialecto().admiteSiExisteEnModificaciones() {
+		err.asignarMotivoNoAdmitidoPorDialecto("'create index if not exists'")
+	}
+	if len(err.mensajes) != 0 {
+		return "", err
+	}
+
+	var sentencia = "create "
+	if o.unico {
+		sentencia += "unique "
+	}
+	sentencia += "index "
+	if o.siNoExiste {
+		sentencia += "if not exists "
+	}
+
+	return sentencia + fmt.Sprintf("%v on %v (%v);", o.nombre, o.tabla, strings.Join(o.columnas, ", ")), nil
+}
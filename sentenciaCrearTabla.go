@@ -0,0 +1,427 @@
+package bdsql
+
+import (
+	"fmt"
+	"strings"
+)
+
+type crearTabla struct {
+	bd *BD
+
+	tabla      string
+	siNoExiste bool
+
+	columnas       []*columna
+	clavePrimaria  []string
+	indices        []string
+	clavesForaneas []*claveForanea
+
+	motor             string
+	juegoDeCaracteres string
+	intercalacion     string
+	comentario        string
+}
+
+// SiNoExiste establece que la tabla se cree solo si no existe:
+// 'create table if not exists'.
+func (o *crearTabla) SiNoExiste() *crearTabla {
+	o.siNoExiste = true
+	return o
+}
+
+// Columna agrega una columna a la tabla y la devuelve para establecer su
+// tipo y sus atributos.
+//
+//	ct := bd.CrearTabla("personas")
+//	ct.Columna("id").Entero().AutoIncremental().ClavePrimaria()
+//	ct.Columna("apellidos").Texto(100).NoNulo()
+//	ct.Columna("alta").FechaHora().NoNulo().PorDefecto("current_timestamp")
+//	err := ct.Ejecutar()
+func (o *crearTabla) Columna(nombre string) *columna {
+	var c = &columna{nombre: nombre}
+	o.columnas = append(o.columnas, c)
+
+	return c
+}
+
+// ClavePrimaria establece la clave primaria de la tabla compuesta por las
+// columnas recibidas.
+func (o *crearTabla) ClavePrimaria(columnas ...string) *crearTabla {
+	o.clavePrimaria = columnas
+	return o
+}
+
+// Indice agrega un índice a la tabla.
+func (o *crearTabla) Indice(nombre string, columnas ...string) *crearTabla {
+	o.indices = append(o.indices, fmt.Sprintf("index %v (%v)", nombre, strings.Join(columnas, ", ")))
+	return o
+}
+
+// IndiceUnico agrega un índice único a la tabla.
+func (o *crearTabla) IndiceUnico(nombre string, columnas ...string) *crearTabla {
+	o.indices = append(o.indices, fmt.Sprintf("unique index %v (%v)", nombre, strings.Join(columnas, ", ")))
+	return o
+}
+
+// ClaveForanea agrega una clave foránea a la tabla compuesta por las
+// columnas recibidas, y la devuelve para establecer la tabla referenciada:
+//
+//	ct.ClaveForanea("persona_id").Referencia("personas", "id").AlEliminar("cascade")
+func (o *crearTabla) ClaveForanea(columnas ...string) *claveForanea {
+	var cf = &claveForanea{tabla: o.tabla, columnas: columnas}
+	o.clavesForaneas = append(o.clavesForaneas, cf)
+
+	return cf
+}
+
+// Motor establece el motor de almacenamiento de la tabla ('engine').
+func (o *crearTabla) Motor(motor string) *crearTabla {
+	o.motor = motor
+	return o
+}
+
+// JuegoDeCaracteres establece el juego de caracteres de la tabla
+// ('default charset').
+func (o *crearTabla) JuegoDeCaracteres(juegoDeCaracteres string) *crearTabla {
+	o.juegoDeCaracteres = juegoDeCaracteres
+	return o
+}
+
+// Intercalacion establece la intercalación de la tabla ('collate').
+func (o *crearTabla) Intercalacion(intercalacion string) *crearTabla {
+	o.intercalacion = intercalacion
+	return o
+}
+
+// Comentario establece el comentario de la tabla.
+func (o *crearTabla) Comentario(comentario string) *crearTabla {
+	o.comentario = comentario
+	return o
+}
+
+// SQL devuelve la sentencia SQL.
+func (o *crearTabla) SQL() (string, error) {
+	return o.generarSQL()
+}
+
+// Ejecutar ejecuta la sentencia SQL.
+func (o *crearTabla) Ejecutar() error {
+	var sentencia, err = o.generarSQL()
+	if err != nil {
+		return err
+	}
+	_, err = ejecutarSentencia(o.bd, o.bd.db, sentencia, nil, false)
+
+	return err
+}
+
+func (o *crearTabla) generarSQL() (string, error) {
+	var err = errorNuevo()
+	// verificar que el nombre de la tabla no se encuentre vacía
+	if o.tabla == "" {
+		err.asignarMotivoNombreDeTablaVacia()
+	}
+	// verificar que la tabla contenga columnas
+	if len(o.columnas) == 0 {
+		err.asignarMotivoNombresDeCamposVacios()
+	}
+	if len(err.mensajes) != 0 {
+		return "", err
+	}
+
+	var definiciones []string
+	for _, c := range o.columnas {
+		definicion, err := c.generarSQL()
+		if err != nil {
+			return "", err
+		}
+		definiciones = append(definiciones, definicion)
+	}
+	if len(o.clavePrimaria) > 0 {
+		definiciones = append(definiciones, fmt.Sprintf("primary key (%v)", strings.Join(o.clavePrimaria, ", ")))
+	}
+	definiciones = append(definiciones, o.indices...)
+	for _, cf := range o.clavesForaneas {
+		definicion, err := cf.generarSQL()
+		if err != nil {
+			return "", err
+		}
+		definiciones = append(definiciones, definicion)
+	}
+
+	var sentencia = "create table "
+	if o.siNoExiste {
+		sentencia += "if not exists "
+	}
+	sentencia += fmt.Sprintf("%v (%v)", o.tabla, strings.Join(definiciones, ", "))
+	// opciones de la tabla
+	if o.motor != "" {
+		sentencia += " engine = " + o.motor
+	}
+	if o.juegoDeCaracteres != "" {
+		sentencia += " default charset = " + o.juegoDeCaracteres
+	}
+	if o.intercalacion != "" {
+		sentencia += " collate = " + o.intercalacion
+	}
+	if o.comentario != "" {
+		sentencia += " comment = " + citarTexto(o.comentario)
+	}
+
+	return sentencia + ";", nil
+}
+
+// -----------------------------------------------------------------------------
+
+// columna representa la definición de una columna en las sentencias
+// 'create table' y 'alter table'.
+type columna struct {
+	nombre string
+	tipo   string
+
+	sinSigno        bool
+	noNulo          bool
+	nulo            bool
+	autoIncremental bool
+	clavePrimaria   bool
+	unico           bool
+	porDefecto      string
+	comentario      string
+	posicion        string // 'first' o 'after columna' (solo en 'alter table')
+}
+
+// Entero establece el tipo 'int' de la columna.
+func (c *columna) Entero() *columna { return c.Tipo("int") }
+
+// EnteroGrande establece el tipo 'bigint' de la columna.
+func (c *columna) EnteroGrande() *columna { return c.Tipo("bigint") }
+
+// EnteroPequeño establece el tipo 'smallint' de la columna.
+func (c *columna) EnteroPequeño() *columna { return c.Tipo("smallint") }
+
+// Decimal establece el tipo 'decimal(precision, escala)' de la columna.
+func (c *columna) Decimal(precision, escala int) *columna {
+	return c.Tipo(fmt.Sprintf("decimal(%v, %v)", precision, escala))
+}
+
+// Flotante establece el tipo 'double' de la columna.
+func (c *columna) Flotante() *columna { return c.Tipo("double") }
+
+// Logico establece el tipo 'boolean' de la columna.
+func (c *columna) Logico() *columna { return c.Tipo("boolean") }
+
+// Texto establece el tipo 'varchar(longitud)' de la columna.
+func (c *columna) Texto(longitud int) *columna {
+	return c.Tipo(fmt.Sprintf("varchar(%v)", longitud))
+}
+
+// TextoLargo establece el tipo 'text' de la columna.
+func (c *columna) TextoLargo() *columna { return c.Tipo("text") }
+
+// Binario establece el tipo 'varbinary(longitud)' de la columna.
+func (c *columna) Binario(longitud int) *columna {
+	return c.Tipo(fmt.Sprintf("varbinary(%v)", longitud))
+}
+
+// Fecha establece el tipo 'date' de la columna.
+func (c *columna) Fecha() *columna { return c.Tipo("date") }
+
+// FechaHora establece el tipo 'datetime' de la columna.
+func (c *columna) FechaHora() *columna { return c.Tipo("datetime") }
+
+// MarcaDeTiempo establece el tipo 'timestamp' de la columna.
+func (c *columna) MarcaDeTiempo() *columna { return c.Tipo("timestamp") }
+
+// JSON establece el tipo 'json' de la columna. En Mariadb, 'json' es un
+// alias de 'longtext' con la verificación de su contenido.
+func (c *columna) JSON() *columna { return c.Tipo("json") }
+
+// Tipo establece el tipo de la columna tal cual se recibe ("char(2)",
+// "enum('a', 'b')", etc.).
+func (c *columna) Tipo(tipo string) *columna {
+	c.tipo = tipo
+	return c
+}
+
+// SinSigno establece el atributo 'unsigned' de la columna numérica.
+func (c *columna) SinSigno() *columna {
+	c.sinSigno = true
+	return c
+}
+
+// NoNulo establece el atributo 'not null' de la columna.
+func (c *columna) NoNulo() *columna {
+	c.noNulo, c.nulo = true, false
+	return c
+}
+
+// Nulo establece el atributo 'null' de la columna.
+func (c *columna) Nulo() *columna {
+	c.nulo, c.noNulo = true, false
+	return c
+}
+
+// AutoIncremental establece el atributo 'auto_increment' de la columna.
+func (c *columna) AutoIncremental() *columna {
+	c.autoIncremental = true
+	return c
+}
+
+// ClavePrimaria establece que la columna es la clave primaria de la tabla.
+func (c *columna) ClavePrimaria() *columna {
+	c.clavePrimaria = true
+	return c
+}
+
+// Unico establece el atributo 'unique' de la columna.
+func (c *columna) Unico() *columna {
+	c.unico = true
+	return c
+}
+
+// PorDefecto establece el valor por defecto de la columna. El valor es una
+// expresión SQL y se escribe tal cual se recibe: PorDefecto("0"),
+// PorDefecto("'activo'"), PorDefecto("current_timestamp").
+func (c *columna) PorDefecto(expresion string) *columna {
+	c.porDefecto = expresion
+	return c
+}
+
+// Comentario establece el comentario de la columna.
+func (c *columna) Comentario(comentario string) *columna {
+	c.comentario = comentario
+	return c
+}
+
+// Primera ubica la columna en la primera posición de la tabla. Solo se
+// aplica al agregar o modificar columnas (ModificarTabla).
+func (c *columna) Primera() *columna {
+	c.posicion = "first"
+	return c
+}
+
+// Despues ubica la columna a continuación de la columna recibida. Solo se
+// aplica al agregar o modificar columnas (ModificarTabla).
+func (c *columna) Despues(columna string) *columna {
+	c.posicion = "after " + columna
+	return c
+}
+
+func (c *columna) generarSQL() (string, error) {
+	if c.nombre == "" {
+		return "", errorNuevo().asignarMotivoNombresDeCamposVacios()
+	}
+	if c.tipo == "" {
+		return "", errorNuevo().asignarMotivoColumnaSinTipo(c.nombre)
+	}
+
+	var partes = []string{c.nombre, c.tipo}
+	if c.sinSigno {
+		partes = append(partes, "unsigned")
+	}
+	if c.noNulo || c.clavePrimaria {
+		partes = append(partes, "not null")
+	} else if c.nulo {
+		partes = append(partes, "null")
+	}
+	if c.porDefecto != "" {
+		partes = append(partes, "default "+c.porDefecto)
+	}
+	if c.autoIncremental {
+		partes = append(partes, "auto_increment")
+	}
+	if c.clavePrimaria {
+		partes = append(partes, "primary key")
+	} else if c.unico {
+		partes = append(partes, "unique")
+	}
+	if c.comentario != "" {
+		partes = append(partes, "comment "+citarTexto(c.comentario))
+	}
+	if c.posicion != "" {
+		partes = append(partes, c.posicion)
+	}
+
+	return strings.Join(partes, " "), nil
+}
+
+// -----------------------------------------------------------------------------
+
+// claveForanea representa la definición de una clave foránea en las
+// sentencias 'create table' y 'alter table'.
+type claveForanea struct {
+	tabla              string
+	nombre             string
+	columnas           []string
+	tablaReferencia    string
+	columnasReferencia []string
+	alEliminar         string
+	alModificar        string
+}
+
+// Nombre establece el nombre de la restricción. Por defecto es
+// 'fk_<tabla>_<columnas>'.
+func (cf *claveForanea) Nombre(nombre string) *claveForanea {
+	cf.nombre = nombre
+	return cf
+}
+
+// Referencia establece la tabla y las columnas referenciadas.
+func (cf *claveForanea) Referencia(tabla string, columnas ...string) *claveForanea {
+	cf.tablaReferencia, cf.columnasReferencia = tabla, columnas
+	return cf
+}
+
+// AlEliminar establece la acción al eliminar el registro referenciado
+// ('cascade', 'set null', 'restrict', 'no action').
+func (cf *claveForanea) AlEliminar(accion string) *claveForanea {
+	cf.alEliminar = accion
+	return cf
+}
+
+// AlModificar establece la acción al modificar el registro referenciado
+// ('cascade', 'set null', 'restrict', 'no action').
+func (cf *claveForanea) AlModificar(accion string) *claveForanea {
+	cf.alModificar = accion
+	return cf
+}
+
+func (cf *claveForanea) generarSQL() (string, error) {
+	if len(cf.columnas) == 0 || len(cf.columnasReferencia) == 0 {
+		return "", errorNuevo().asignarMotivoNombresDeCamposVacios()
+	}
+	if cf.tablaReferencia == "" {
+		return "", errorNuevo().asignarMotivoNombreDeTablaVacia()
+	}
+
+	var nombre = cf.nombre
+	if nombre == "" {
+		nombre = fmt.Sprintf("fk_%v_%v", cf.tabla, strings.Join(cf.columnas, "_"))
+	}
+	var sentencia = fmt.Sprintf("constraint %v foreign key (%v) references %v (%v)",
+		nombre, strings.Join(cf.columnas, ", "), cf.tablaReferencia, strings.Join(cf.columnasReferencia, ", "))
+	if cf.alEliminar != "" {
+		sentencia += " on delete " + cf.alEliminar
+	}
+	if cf.alModificar != "" {
+		sentencia += " on update " + cf.alModificar
+	}
+
+	return sentencia, nil
+}
+
+// citarTexto devuelve el texto entre comillas simples, escapando las
+// comillas, las barras invertidas y los caracteres de control que el motor
+// interpreta (nulo, saltos de línea y 'ctrl+z').
+func citarTexto(texto string) string {
+	return "'" + escaparTexto.Replace(texto) + "'"
+}
+
+var escaparTexto = strings.NewReplacer(
+	`\`, `\\`,
+	`'`, `''`,
+	"\x00", `\0`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\x1a", `\Z`,
+)
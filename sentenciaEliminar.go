@@ -1,192 +1,296 @@
-package bdsql
-
-import (
-	"fmt"
-
-	"database/sql"
-)
-
-type eliminar struct {
-	bd *BD
-	tx *sql.Tx
-
-	tabla string
-
-	condicion        string
-	condicionValores []interface{}
-
-	limite int
-
-	senSQLExiste bool
-	senSQLNombre string
-	senSQL       string
-}
-
-// Tabla establece el nombre de la tabla donde se eliminarán los registros.
-func (o *eliminar) Tabla(tabla string) *eliminar {
-	if o.senSQLExiste {
-		return o
-	}
-
-	o.tabla = tabla
-	return o
-}
-
-// Condicion implementa la cláusula 'where' de la sentencia 'delete'.
-func (o *eliminar) Condicion(condicion string, valores ...interface{}) *eliminar {
-	if !o.senSQLExiste {
-		o.condicion = condicion
-	}
-	o.condicionValores = valores
-
-	return o
-}
-
-// Limitar implementa la cláusula 'limit' de la sentencia 'delete'.
-func (o *eliminar) Limitar(limite int) *eliminar {
-	o.limite = limite
-
-	return o
-}
-
-// SQL devuelve la sentencia SQL.
-func (o *eliminar) SQL() (string, error) {
-	return o.generarSQL()
-}
-
-// SentenciaPreparada devuelve una sentencia preparada para ser utilizada
-// múltiples veces.
-func (o *eliminar) SentenciaPreparada() (*sentenciaPreparadaEliminar, error) {
-	var sentencia, err = o.generarSQL()
-	if err != nil {
-		return nil, err
-	}
-
-	var sp = new(sentenciaPreparadaEliminar)
-	if o.tx == nil {
-		// ejecución fuera de una transacción
-		sp.stmt, err = o.bd.db.Prepare(sentencia)
-	} else {
-		// ejecución dentro de una transacción
-		sp.stmt, err = o.tx.Prepare(sentencia)
-	}
-	if err != nil {
-		return nil, errorNuevo().asignarOrigen(err).asignarMotivoSentenciaPreparadaCrear()
-	}
-
-	return sp, nil
-}
-
-// Ejecutar ejecuta la sentencia SQL.
-func (o *eliminar) Ejecutar() error {
-	var sentencia, err = o.generarSQL()
-	if err != nil {
-		return err
-	}
-
-	var errEjec = errorNuevo()
-	// verificar que los valores de la condición no se encuentren vacíos
-	if len(o.condicionValores) == 0 {
-		errEjec.asignarMotivoValoresCondicionVacia()
-	}
-	if len(errEjec.mensajes) != 0 {
-		return errEjec
-	}
-
-	var res sql.Result
-	if o.tx == nil {
-		// ejecución fuera de una transacción
-		res, err = o.bd.db.Exec(sentencia, o.condicionValores...)
-	} else {
-		// ejecución dentro de una transacción
-		res, err = o.tx.Exec(sentencia, o.condicionValores...)
-	}
-	if err != nil {
-		return resolverErrorMysql(err)
-	}
-
-	if cant, err := res.RowsAffected(); err != nil {
-		return errorNuevo().asignarMotivoObtencionDeRegistrosAfectados()
-	} else if cant == 0 {
-		return errorNuevo().asignarMotivoNingunRegistroAfectado()
-	}
-
-	return nil
-}
-
-func (o *eliminar) generarSQL() (string, error) {
-	if o.senSQLExiste {
-		return o.senSQL, nil
-	}
-
-	var err = errorNuevo()
-	// verificar que el nombre de la tabla no se encuentre vacía
-	if o.tabla == "" {
-		err.asignarMotivoNombreDeTablaVacia()
-	}
-	// verificar que la condición no se encuentre vacía
-	// no se permite realizar eliminaciones sin condición
-	if o.condicion == "" {
-		err.asignarMotivoCondicionVacia()
-	}
-	// verificar que los valores de la condición no se encuentren vacíos
-	if len(o.condicionValores) == 0 {
-		err.asignarMotivoValoresCondicionVacia()
-	}
-	if len(err.mensajes) != 0 {
-		return "", err
-	}
-
-	// sentencia con cláusula where
-	var sentencia = fmt.Sprintf("delete from %v where %v", o.tabla, o.condicion)
-	// limit
-	if o.limite > 0 {
-		sentencia += fmt.Sprintf(" limit %v", o.limite)
-	}
-
-	sentencia += ";"
-	o.bd.guardarSentenciaSQL(o.senSQLNombre, sentencia)
-
-	return sentencia, nil
-}
-
-// -----------------------------------------------------------------------------
-
-type sentenciaPreparadaEliminar struct {
-	stmt *sql.Stmt
-
-	valores []interface{}
-}
-
-// Valores establece los valores que recibirán los campos a actualizar.
-func (o *sentenciaPreparadaEliminar) Valores(valores ...interface{}) *sentenciaPreparadaEliminar {
-	o.valores = valores
-
-	return o
-}
-
-// Ejecutar ejecuta la sentencia SQL.
-func (o *sentenciaPreparadaEliminar) Ejecutar() error {
-	// verificar que los valores no se encuentren vacíos
-	if len(o.valores) == 0 {
-		return errorNuevo().asignarMotivoValoresVacios()
-	}
-
-	res, err := o.stmt.Exec(o.valores...)
-	if err != nil {
-		return resolverErrorMysql(err)
-	}
-
-	if cant, err := res.RowsAffected(); err != nil {
-		return errorNuevo().asignarMotivoObtencionDeRegistrosAfectados()
-	} else if cant == 0 {
-		return errorNuevo().asignarMotivoNingunRegistroAfectado()
-	}
-
-	return nil
-}
-
-// Cerrar cierra la sentencia preparada.
-func (o *sentenciaPreparadaEliminar) Cerrar() error {
-	return resolverErrorMysql(o.stmt.Close())
-}
+package bdsql
+
+import (
+	"fmt"
+
+	"database/sql"
+)
+
+type eliminar struct {
+	bd       *BD
+	conexion Conexion // conexión sobre la que se ejecuta la sentencia: el pool o una transacción
+
+	tabla string
+
+	condicion        string
+	condicionValores []interface{}
+
+	limite int
+
+	permitirCero bool // no considerar un error que ningún registro resulte afectado
+
+	err error // error producido al establecer los elementos de la sentencia
+
+	senSQLExiste bool
+	senSQLNombre string
+	senSQL       string
+}
+
+// nuevoEliminar crea la sentencia sobre la conexión recibida. Si la sentencia fue
+// generada previamente con el mismo nombre, se reutiliza.
+func nuevoEliminar(bd *BD, conexion Conexion, nombre string) *eliminar {
+	var o = &eliminar{bd: bd, conexion: conexion}
+	if nombre != "-" {
+		o.senSQLNombre = nombre
+		o.senSQL, o.senSQLExiste = o.bd.obtenerSentenciaSQL(nombre)
+	}
+
+	return o
+}
+
+// Tabla establece el nombre de la tabla donde se eliminarán los registros.
+// Si la tabla fue registrada con BD.BorradoLogico(), los registros no se
+// eliminan: se asigna el momento de la eliminación en el campo registrado.
+func (o *eliminar) Tabla(tabla string) *eliminar {
+	if o.senSQLExiste {
+		return o
+	}
+
+	o.tabla = tabla
+	return o
+}
+
+// Condicion implementa la cláusula 'where' de la sentencia 'delete'.
+// La condición puede contener parámetros nombrados (':nombre') cuyos valores
+// se reciben en un mapa, una estructura o con sql.Named(); '@nombre' es una
+// variable de sesión y no se reemplaza.
+// También puede recibir un predicado (Igual, Entre, Y, O, etc.).
+// Los valores que sean listas (slices) se expanden en tantos parámetros como
+// elementos contengan: Condicion("id in (?)", ids).
+func (o *eliminar) Condicion(condicion interface{}, valores ...interface{}) *eliminar {
+	texto, valores, err := resolverCondicion(condicion, valores, false)
+	if err != nil {
+		o.err = err
+		return o
+	}
+	if !o.senSQLExiste {
+		o.condicion = texto
+	}
+	o.condicionValores = valores
+
+	return o
+}
+
+// Limitar implementa la cláusula 'limit' de la sentencia 'delete'.
+func (o *eliminar) Limitar(limite int) *eliminar {
+	o.limite = limite
+
+	return o
+}
+
+// PermitirCeroAfectados establece que no se considere un error que la
+// sentencia no afecte a ningún registro de la tabla.
+func (o *eliminar) PermitirCeroAfectados() *eliminar {
+	o.permitirCero = true
+
+	return o
+}
+
+// SQL devuelve la sentencia SQL.
+func (o *eliminar) SQL() (string, error) {
+	var sentencia, err = o.generarSQL()
+	if err != nil {
+		return "", err
+	}
+	sentencia, _, err = expandirSentencia(sentencia, o.condicionValores)
+
+	return sentencia, err
+}
+
+// SQLInterpolado devuelve la sentencia SQL con los valores incorporados como
+// literales, para registrarla o copiarla en una consola. Solo debe utilizarse
+// con ese fin: las sentencias se ejecutan siempre con parámetros.
+func (o *eliminar) SQLInterpolado() (string, error) {
+	var sentencia, err = o.generarSQL()
+	if err != nil {
+		return "", err
+	}
+	sentencia, valores, err := expandirSentencia(sentencia, o.condicionValores)
+	if err != nil {
+		return "", err
+	}
+
+	return o.bd.interpolarSentencia(sentencia, valores)
+}
+
+// SentenciaPreparada devuelve una sentencia preparada para ser utilizada
+// múltiples veces.
+func (o *eliminar) SentenciaPreparada() (*sentenciaPreparadaEliminar, error) {
+	var sentencia, err = o.generarSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	var sp = &sentenciaPreparadaEliminar{bd: o.bd, sentencia: sentencia, conexion: o.conexion, permitirCero: o.permitirCero}
+	if sp.stmt, err = prepararSentencia(o.bd, o.conexion, sentencia); err != nil {
+		return nil, err
+	}
+
+	return sp, nil
+}
+
+// Ejecutar ejecuta la sentencia SQL.
+func (o *eliminar) Ejecutar() error {
+	_, err := o.ejecutar(false)
+	return err
+}
+
+// EjecutarResultado ejecuta la sentencia SQL y devuelve el resultado de la
+// ejecución: registros afectados y advertencias del motor.
+func (o *eliminar) EjecutarResultado() (Resultado, error) {
+	return o.ejecutar(true)
+}
+
+func (o *eliminar) ejecutar(conAdvertencias bool) (Resultado, error) {
+	var sentencia, err = o.generarSQL()
+	if err != nil {
+		return Resultado{}, err
+	}
+
+	var errEjec = errorNuevo()
+	// verificar que los valores de la condición no se encuentren vacíos
+	if len(o.condicionValores) == 0 {
+		errEjec.asignarMotivoValoresCondicionVacia()
+	}
+	if len(errEjec.mensajes) != 0 {
+		return Resultado{}, errEjec
+	}
+
+	sentencia, valores, err := expandirSentencia(sentencia, o.condicionValores)
+	if err != nil {
+		return Resultado{}, err
+	}
+
+	res, err := ejecutarSentencia(o.bd, o.conexion, sentencia, valores, conAdvertencias)
+	if err != nil {
+		return res, err
+	}
+	if res.RegistrosAfectados == 0 && !o.permitirCero {
+		return res, errorNuevo().asignarMotivoNingunRegistroAfectado()
+	}
+
+	return res, nil
+}
+
+func (o *eliminar) generarSQL() (string, error) {
+	if o.err != nil {
+		return "", o.err
+	}
+	if o.senSQLExiste {
+		return o.senSQL, nil
+	}
+
+	var err = errorNuevo()
+	// verificar que el nombre de la tabla no se encuentre vacía
+	if o.tabla == "" {
+		err.asignarMotivoNombreDeTablaVacia()
+	}
+	// verificar que la condición no se encuentre vacía
+	// no se permite realizar eliminaciones sin condición
+	if o.condicion == "" {
+		err.asignarMotivoCondicionVacia()
+	}
+	// verificar que los valores de la condición no se encuentren vacíos
+	if len(o.condicionValores) == 0 {
+		err.asignarMotivoValoresCondicionVacia()
+	}
+	if len(err.mensajes) != 0 {
+		return "", err
+	}
+
+	// sentencia con cláusula where
+	var sentencia string
+	if campo, ok := o.bd.campoBorradoLogico(o.tabla); ok {
+		// borrado lógico: registrar el momento de la eliminación
+		sentencia = fmt.Sprintf("update %v set %v = now() where (%v) and %v is null", o.tabla, campo, o.condicion, campo)
+	} else {
+		sentencia = fmt.Sprintf("delete from %v where %v", o.tabla, o.condicion)
+	}
+	// limit
+	if o.limite > 0 {
+		sentencia += fmt.Sprintf(" limit %v", o.limite)
+	}
+
+	sentencia += ";"
+	o.bd.guardarSentenciaSQL(o.senSQLNombre, sentencia)
+
+	return sentencia, nil
+}
+
+// -----------------------------------------------------------------------------
+
+type sentenciaPreparadaEliminar struct {
+	bd        *BD
+	stmt      *sql.Stmt
+	sentencia string   // sentencia SQL con la que se creó la sentencia preparada
+	conexion  Conexion // conexión sobre la que se creó la sentencia preparada
+
+	valores []interface{}
+
+	permitirCero bool // no considerar un error que ningún registro resulte afectado
+}
+
+// Valores establece los valores que recibirán los campos a actualizar.
+func (o *sentenciaPreparadaEliminar) Valores(valores ...interface{}) *sentenciaPreparadaEliminar {
+	o.valores = valores
+
+	return o
+}
+
+// SQLInterpolado devuelve la sentencia SQL con los valores incorporados como
+// literales, para registrarla o copiarla en una consola. Solo debe utilizarse
+// con ese fin: las sentencias se ejecutan siempre con parámetros.
+func (o *sentenciaPreparadaEliminar) SQLInterpolado() (string, error) {
+	return o.bd.interpolarSentencia(o.sentencia, o.valores)
+}
+
+// PermitirCeroAfectados establece que no se considere un error que la
+// sentencia no afecte a ningún registro de la tabla.
+func (o *sentenciaPreparadaEliminar) PermitirCeroAfectados() *sentenciaPreparadaEliminar {
+	o.permitirCero = true
+
+	return o
+}
+
+// Ejecutar ejecuta la sentencia SQL.
+func (o *sentenciaPreparadaEliminar) Ejecutar() error {
+	_, err := o.ejecutar(false)
+	return err
+}
+
+// EjecutarResultado ejecuta la sentencia SQL y devuelve el resultado de la
+// ejecución. Las advertencias del motor solo se obtienen cuando la sentencia
+// preparada pertenece a una transacción.
+func (o *sentenciaPreparadaEliminar) EjecutarResultado() (Resultado, error) {
+	return o.ejecutar(true)
+}
+
+func (o *sentenciaPreparadaEliminar) ejecutar(conAdvertencias bool) (Resultado, error) {
+	// verificar que los valores no se encuentren vacíos
+	if len(o.valores) == 0 {
+		return Resultado{}, errorNuevo().asignarMotivoValoresVacios()
+	}
+	// verificar que no existan valores que deban incorporarse a la sentencia
+	if requiereExpansion(o.valores) {
+		return Resultado{}, errorNuevo().asignarMotivoSentenciaPreparadaValorNoAdmitido()
+	}
+
+	res, err := ejecutarSentenciaPreparada(o.bd, o.stmt, o.sentencia, o.conexion, o.valores, conAdvertencias)
+	if err != nil {
+		return res, err
+	}
+	if res.RegistrosAfectados == 0 && !o.permitirCero {
+		return res, errorNuevo().asignarMotivoNingunRegistroAfectado()
+	}
+
+	return res, nil
+}
+
+// Cerrar cierra la sentencia preparada.
+func (o *sentenciaPreparadaEliminar) Cerrar() error {
+	if o.stmt == nil {
+		// sentencia creada en el modo simulación
+		return nil
+	}
+	return resolverErrorMysql(o.stmt.Close())
+}
@@ -0,0 +1,45 @@
+package bdsql
+
+type eliminarTabla struct {
+	bd *BD
+
+	tabla    string
+	siExiste bool
+}
+
+// SiExiste establece que la tabla se elimine solo si existe:
+// 'drop table if exists'.
+func (o *eliminarTabla) SiExiste() *eliminarTabla {
+	o.siExiste = true
+	return o
+}
+
+// SQL devuelve la sentencia SQL.
+func (o *eliminarTabla) SQL() (string, error) {
+	return o.generarSQL()
+}
+
+// Ejecutar ejecuta la sentencia SQL.
+func (o *eliminarTabla) Ejecutar() error {
+	var sentencia, err = o.generarSQL()
+	if err != nil {
+		return err
+	}
+	_, err = ejecutarSentencia(o.bd, o.bd.db, sentencia, nil, false)
+
+	return err
+}
+
+func (o *eliminarTabla) generarSQL() (string, error) {
+	// verificar que el nombre de la tabla no se encuentre vacía
+	if o.tabla == "" {
+		return "", errorNuevo().asignarMotivoNombreDeTablaVacia()
+	}
+
+	var sentencia = "drop table "
+	if o.siExiste {
+		sentencia += "if exists "
+	}
+
+	return sentencia + o.tabla + ";", nil
+}
@@ -1,232 +1,471 @@
-package bdsql
-
-import (
-	"fmt"
-
-	"database/sql"
-)
-
-type modificar struct {
-	bd *BD
-	tx *sql.Tx
-
-	tabla   string
-	campos  []string
-	valores []interface{}
-
-	condicion        string
-	condicionValores []interface{}
-
-	limite int
-
-	senSQLExiste bool
-	senSQLNombre string
-	senSQL       string
-}
-
-// Tabla establece el nombre de la tabla a modificar.
-func (o *modificar) Tabla(tabla string) *modificar {
-	if o.senSQLExiste {
-		return o
-	}
-
-	o.tabla = tabla
-	return o
-}
-
-// Campos establece los nombres de campos a actualizar.
-func (o *modificar) Campos(campos ...string) *modificar {
-	o.campos = campos
-	return o
-}
-
-// Valores establece los valores que recibirán los campos a actualizar.
-func (o *modificar) Valores(valores ...interface{}) *modificar {
-	o.valores = valores
-
-	return o
-}
-
-// Condicion implementa la cláusula 'where' de la sentencia 'update'.
-func (o *modificar) Condicion(condicion string, valores ...interface{}) *modificar {
-	if !o.senSQLExiste {
-		o.condicion = condicion
-	}
-	o.condicionValores = valores
-
-	return o
-}
-
-// Limitar implementa la cláusula 'limit' de la sentencia 'update'.
-func (o *modificar) Limitar(limite int) *modificar {
-	o.limite = limite
-
-	return o
-}
-
-// SQL devuelve la sentencia SQL.
-func (o *modificar) SQL() (string, error) {
-	return o.generarSQL()
-}
-
-// SentenciaPreparada devuelve una sentencia preparada para ser utilizada
-// múltiples veces.
-func (o *modificar) SentenciaPreparada() (*sentenciaPreparadaModificar, error) {
-	var sentencia, err = o.generarSQL()
-	if err != nil {
-		return nil, err
-	}
-
-	var sp = &sentenciaPreparadaModificar{cantCampos: len(o.campos)}
-	if o.tx == nil {
-		// ejecución fuera de una transacción
-		sp.stmt, err = o.bd.db.Prepare(sentencia)
-	} else {
-		// ejecución dentro de una transacción
-		sp.stmt, err = o.tx.Prepare(sentencia)
-	}
-	if err != nil {
-		return nil, errorNuevo().asignarOrigen(err).asignarMotivoSentenciaPreparadaCrear()
-	}
-
-	return sp, nil
-}
-
-// Ejecutar ejecuta la sentencia SQL.
-func (o *modificar) Ejecutar() error {
-	var sentencia, err = o.generarSQL()
-	if err != nil {
-		return err
-	}
-
-	var errEjec = errorNuevo()
-	// verificar que los valores no se encuentren vacíos
-	if len(o.valores) == 0 {
-		errEjec.asignarMotivoValoresVacios()
-	}
-	// verificar que la cantidad de campos coincida con la cantidad de valores recibidos
-	if len(o.campos) != len(o.valores) {
-		errEjec.asignarMotivoCamposValoresDiferenteCantidad()
-	}
-	// verificar que los valores de la condición no se encuentren vacíos
-	if len(o.condicionValores) == 0 {
-		errEjec.asignarMotivoValoresCondicionVacia()
-	}
-	if len(errEjec.mensajes) != 0 {
-		return errEjec
-	}
-
-	var res sql.Result
-	if o.tx == nil {
-		// ejecución fuera de una transacción
-		res, err = o.bd.db.Exec(sentencia, append(o.valores, o.condicionValores...)...)
-	} else {
-		// ejecución dentro de una transacción
-		res, err = o.tx.Exec(sentencia, append(o.valores, o.condicionValores...)...)
-	}
-	if err != nil {
-		return resolverErrorMysql(err)
-	}
-
-	if cant, err := res.RowsAffected(); err != nil {
-		return errorNuevo().asignarMotivoObtencionDeRegistrosAfectados()
-	} else if cant == 0 {
-		return errorNuevo().asignarMotivoNingunRegistroAfectado()
-	}
-
-	return nil
-}
-
-func (o *modificar) generarSQL() (string, error) {
-	if o.senSQLExiste {
-		return o.senSQL, nil
-	}
-
-	var err = errorNuevo()
-	// verificar que el nombre de la tabla no se encuentre vacía
-	if o.tabla == "" {
-		err.asignarMotivoNombreDeTablaVacia()
-	}
-	// verificar que los nombres de campos no se encuentren vacíos
-	if len(o.campos) == 0 {
-		err.asignarMotivoNombresDeCamposVacios()
-	}
-	// verificar que la condición no se encuentre vacía
-	// no se permite realizar modificaciones sin condición
-	if o.condicion == "" {
-		err.asignarMotivoCondicionVacia()
-	}
-	if len(err.mensajes) != 0 {
-		return "", err
-	}
-
-	// campos
-	var campos string
-	for _, v := range o.campos {
-		if campos != "" {
-			campos += ", "
-		}
-		campos += v + " = ?"
-	}
-	// sentencia con cláusula where
-	var sentencia = fmt.Sprintf("update %v set %v where %v", o.tabla, campos, o.condicion)
-	// limit
-	if o.limite > 0 {
-		sentencia += fmt.Sprintf(" limit %v", o.limite)
-	}
-
-	sentencia += ";"
-	o.bd.guardarSentenciaSQL(o.senSQLNombre, sentencia)
-
-	return sentencia, nil
-}
-
-// -----------------------------------------------------------------------------
-
-type sentenciaPreparadaModificar struct {
-	stmt *sql.Stmt
-
-	cantCampos int
-	valores    []interface{}
-}
-
-// Valores establece los valores que recibirán los campos a actualizar.
-func (o *sentenciaPreparadaModificar) Valores(valores ...interface{}) *sentenciaPreparadaModificar {
-	o.valores = valores
-
-	return o
-}
-
-// Ejecutar ejecuta la sentencia SQL.
-func (o *sentenciaPreparadaModificar) Ejecutar() error {
-	var errEjec = errorNuevo()
-	// verificar que los valores no se encuentren vacíos
-	if len(o.valores) == 0 {
-		return errEjec.asignarMotivoValoresVacios()
-	}
-	// verificar que la cantidad de campos coincida con la cantidad de valores recibidos
-	if o.cantCampos != len(o.valores) {
-		errEjec.asignarMotivoCamposValoresDiferenteCantidad()
-	}
-	if len(errEjec.mensajes) != 0 {
-		return errEjec
-	}
-
-	res, err := o.stmt.Exec(o.valores...)
-	if err != nil {
-		return resolverErrorMysql(err)
-	}
-
-	if cant, err := res.RowsAffected(); err != nil {
-		return errorNuevo().asignarMotivoObtencionDeRegistrosAfectados()
-	} else if cant == 0 {
-		return errorNuevo().asignarMotivoNingunRegistroAfectado()
-	}
-
-	return nil
-}
-
-// Cerrar cierra la sentencia preparada.
-func (o *sentenciaPreparadaModificar) Cerrar() error {
-	return resolverErrorMysql(o.stmt.Close())
-}
+package bdsql
+
+import (
+	"context"
+	"fmt"
+
+	"database/sql"
+)
+
+type modificar struct {
+	bd       *BD
+	conexion Conexion // conexión sobre la que se ejecuta la sentencia: el pool o una transacción
+
+	tabla   string
+	campos  []string
+	valores []interface{}
+
+	expresionesCampos  []string      // campos que reciben una expresión SQL
+	expresionesValores []interface{} // expresiones SQL de los campos
+
+	condicion        string
+	condicionValores []interface{}
+
+	limite int
+
+	permitirCero bool // no considerar un error que ningún registro resulte afectado
+
+	versionCampo string      // campo de la tabla que mantiene la versión del registro (bloqueo optimista)
+	versionValor interface{} // valor actual de la versión del registro
+
+	err error // error producido al establecer los elementos de la sentencia
+
+	senSQLExiste  bool
+	senSQLNombre  string
+	senSQL        string
+	senSQLVersion string // sentencia que verifica la existencia del registro (ConVersion)
+}
+
+// nuevoModificar crea la sentencia sobre la conexión recibida. Si la sentencia fue
+// generada previamente con el mismo nombre, se reutiliza.
+func nuevoModificar(bd *BD, conexion Conexion, nombre string) *modificar {
+	var o = &modificar{bd: bd, conexion: conexion}
+	if nombre != "-" {
+		o.senSQLNombre = nombre
+		o.senSQL, o.senSQLExiste = o.bd.obtenerSentenciaSQL(nombre)
+		o.senSQLVersion, _ = o.bd.obtenerSentenciaSQL(nombre + "#version")
+	}
+
+	return o
+}
+
+// Tabla establece el nombre de la tabla a modificar.
+func (o *modificar) Tabla(tabla string) *modificar {
+	if o.senSQLExiste {
+		return o
+	}
+
+	o.tabla = tabla
+	return o
+}
+
+// Campos establece los nombres de campos a actualizar.
+func (o *modificar) Campos(campos ...string) *modificar {
+	o.campos = campos
+	return o
+}
+
+// Valores establece los valores que recibirán los campos a actualizar.
+func (o *modificar) Valores(valores ...interface{}) *modificar {
+	o.valores = valores
+
+	return o
+}
+
+// Expresion establece que el campo recibirá el resultado de una expresión
+// SQL en lugar de un valor. La expresión puede contener parámetros ('?').
+//
+//	Ejemplo:
+//	err := bd.
+//		Modificar("productosDescontarStock").
+//		Tabla("productos").
+//		Expresion("stock", "stock - ?", 3).
+//		Condicion("id = ?", 2).
+//		Ejecutar()
+func (o *modificar) Expresion(campo, expresion string, valores ...interface{}) *modificar {
+	o.expresionesCampos = append(o.expresionesCampos, campo)
+	o.expresionesValores = append(o.expresionesValores, Expr(expresion, valores...))
+
+	return o
+}
+
+// Condicion implementa la cláusula 'where' de la sentencia 'update'.
+// La condición puede contener parámetros nombrados (':nombre') cuyos valores
+// se reciben en un mapa, una estructura o con sql.Named(); '@nombre' es una
+// variable de sesión y no se reemplaza.
+// También puede recibir un predicado (Igual, Entre, Y, O, etc.).
+// Los valores que sean listas (slices) se expanden en tantos parámetros como
+// elementos contengan: Condicion("id in (?)", ids).
+func (o *modificar) Condicion(condicion interface{}, valores ...interface{}) *modificar {
+	texto, valores, err := resolverCondicion(condicion, valores, false)
+	if err != nil {
+		o.err = err
+		return o
+	}
+	if !o.senSQLExiste {
+		o.condicion = texto
+	}
+	o.condicionValores = valores
+
+	return o
+}
+
+// ConVersion implementa el bloqueo optimista: agrega a la condición que el
+// campo de versión contenga el valor actual e incrementa dicho campo en la
+// modificación. Si el registro existe pero su versión ha cambiado, la
+// ejecución devuelve un error de conflicto de versión.
+func (o *modificar) ConVersion(campo string, valorActual interface{}) *modificar {
+	o.versionCampo = campo
+	o.versionValor = valorActual
+
+	return o
+}
+
+// Limitar implementa la cláusula 'limit' de la sentencia 'update'.
+func (o *modificar) Limitar(limite int) *modificar {
+	o.limite = limite
+
+	return o
+}
+
+// PermitirCeroAfectados establece que no se considere un error que la
+// sentencia no afecte a ningún registro de la tabla.
+func (o *modificar) PermitirCeroAfectados() *modificar {
+	o.permitirCero = true
+
+	return o
+}
+
+// SQL devuelve la sentencia SQL.
+func (o *modificar) SQL() (string, error) {
+	var sentencia, err = o.generarSQL()
+	if err != nil {
+		return "", err
+	}
+	sentencia, _, err = expandirSentencia(sentencia, o.valoresSentencia())
+
+	return sentencia, err
+}
+
+// SQLInterpolado devuelve la sentencia SQL con los valores incorporados como
+// literales, para registrarla o copiarla en una consola. Solo debe utilizarse
+// con ese fin: las sentencias se ejecutan siempre con parámetros.
+func (o *modificar) SQLInterpolado() (string, error) {
+	var sentencia, err = o.generarSQL()
+	if err != nil {
+		return "", err
+	}
+	sentencia, valores, err := expandirSentencia(sentencia, o.valoresSentencia())
+	if err != nil {
+		return "", err
+	}
+
+	return o.bd.interpolarSentencia(sentencia, valores)
+}
+
+// SentenciaPreparada devuelve una sentencia preparada para ser utilizada
+// múltiples veces.
+// Las expresiones establecidas con Expresion() se incorporan en la sentencia
+// preparada, por lo tanto no pueden contener parámetros.
+// Con ConVersion(), cada ejecución recibe como último valor la versión
+// actual del registro y verifica el conflicto de versión.
+func (o *modificar) SentenciaPreparada() (*sentenciaPreparadaModificar, error) {
+	var sentencia, err = o.generarSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	// incorporar las expresiones: los parámetros de los campos y de la
+	// condición se mantienen para ser recibidos en cada ejecución.
+	if len(o.expresionesValores) > 0 {
+		for _, e := range o.expresionesValores {
+			if len(e.(expresion).valores) > 0 {
+				return nil, errorNuevo().asignarMotivoSentenciaPreparadaValorNoAdmitido()
+			}
+		}
+		var valores = append(make([]interface{}, len(o.campos)), o.expresionesValores...)
+		if sentencia, _, err = expandirSentencia(sentencia, valores); err != nil {
+			return nil, err
+		}
+	}
+
+	var sp = &sentenciaPreparadaModificar{bd: o.bd, sentencia: sentencia, conexion: o.conexion, cantValores: len(parametrosSQL(sentencia)), permitirCero: o.permitirCero}
+	if o.versionCampo != "" {
+		sp.sentenciaVersion = o.senSQLVersion
+	}
+	if sp.stmt, err = prepararSentencia(o.bd, o.conexion, sentencia); err != nil {
+		return nil, err
+	}
+
+	return sp, nil
+}
+
+// Ejecutar ejecuta la sentencia SQL.
+func (o *modificar) Ejecutar() error {
+	_, err := o.ejecutar(false)
+	return err
+}
+
+// EjecutarResultado ejecuta la sentencia SQL y devuelve el resultado de la
+// ejecución: registros afectados y advertencias del motor.
+func (o *modificar) EjecutarResultado() (Resultado, error) {
+	return o.ejecutar(true)
+}
+
+func (o *modificar) ejecutar(conAdvertencias bool) (Resultado, error) {
+	var sentencia, err = o.generarSQL()
+	if err != nil {
+		return Resultado{}, err
+	}
+
+	var errEjec = errorNuevo()
+	// verificar que los valores no se encuentren vacíos
+	if len(o.valores) == 0 && len(o.expresionesValores) == 0 {
+		errEjec.asignarMotivoValoresVacios()
+	}
+	// verificar que la cantidad de campos coincida con la cantidad de valores recibidos
+	if len(o.campos) != len(o.valores) {
+		errEjec.asignarMotivoCamposValoresDiferenteCantidad()
+	}
+	// verificar que los valores de la condición no se encuentren vacíos
+	if len(o.condicionValores) == 0 {
+		errEjec.asignarMotivoValoresCondicionVacia()
+	}
+	if len(errEjec.mensajes) != 0 {
+		return Resultado{}, errEjec
+	}
+
+	sentencia, valores, err := expandirSentencia(sentencia, o.valoresSentencia())
+	if err != nil {
+		return Resultado{}, err
+	}
+
+	res, err := ejecutarSentencia(o.bd, o.conexion, sentencia, valores, conAdvertencias)
+	if err != nil {
+		return res, err
+	}
+	if res.RegistrosAfectados == 0 && o.versionCampo != "" {
+		// verificar si el registro existe: en ese caso, su versión ha cambiado
+		existe, err := o.existeRegistro()
+		if err != nil {
+			return res, err
+		}
+		if existe {
+			return res, errorNuevo().asignarMotivoConflictoDeVersion()
+		}
+	}
+	if res.RegistrosAfectados == 0 && !o.permitirCero {
+		return res, errorNuevo().asignarMotivoNingunRegistroAfectado()
+	}
+
+	return res, nil
+}
+
+func (o *modificar) generarSQL() (string, error) {
+	if o.err != nil {
+		return "", o.err
+	}
+	if o.senSQLExiste {
+		// la sentencia almacenada debe haberse generado con el mismo uso de
+		// ConVersion: de lo contrario sus parámetros no coinciden con los
+		// valores
+		if (o.versionCampo != "") != (o.senSQLVersion != "") {
+			return "", errorNuevo().asignarMotivoVersionIncompatible(o.senSQLNombre)
+		}
+		return o.senSQL, nil
+	}
+
+	var err = errorNuevo()
+	// verificar que el nombre de la tabla no se encuentre vacía
+	if o.tabla == "" {
+		err.asignarMotivoNombreDeTablaVacia()
+	}
+	// verificar que los nombres de campos no se encuentren vacíos
+	if len(o.campos) == 0 && len(o.expresionesCampos) == 0 {
+		err.asignarMotivoNombresDeCamposVacios()
+	}
+	// verificar que la condición no se encuentre vacía
+	// no se permite realizar modificaciones sin condición
+	if o.condicion == "" {
+		err.asignarMotivoCondicionVacia()
+	}
+	if len(err.mensajes) != 0 {
+		return "", err
+	}
+
+	// campos
+	var campos string
+	for _, v := range append(append([]string{}, o.campos...), o.expresionesCampos...) {
+		if campos != "" {
+			campos += ", "
+		}
+		campos += v + " = ?"
+	}
+	// sentencia con cláusula where
+	var sentencia string
+	if o.versionCampo == "" {
+		sentencia = fmt.Sprintf("update %v set %v where %v", o.tabla, campos, o.condicion)
+	} else {
+		// bloqueo optimista: incrementar la versión y verificar su valor actual
+		campos += fmt.Sprintf(", %v = %v + 1", o.versionCampo, o.versionCampo)
+		sentencia = fmt.Sprintf("update %v set %v where (%v) and %v = ?", o.tabla, campos, o.condicion, o.versionCampo)
+
+		// sentencia utilizada para verificar la existencia del registro
+		o.senSQLVersion = fmt.Sprintf("select count(*) from %v where %v;", o.tabla, o.condicion)
+		o.bd.guardarSentenciaSQL(o.senSQLNombre+"#version", o.senSQLVersion)
+	}
+	// limit
+	if o.limite > 0 {
+		sentencia += fmt.Sprintf(" limit %v", o.limite)
+	}
+
+	sentencia += ";"
+	o.bd.guardarSentenciaSQL(o.senSQLNombre, sentencia)
+
+	return sentencia, nil
+}
+
+// valoresSentencia devuelve los valores de la sentencia en el orden de sus
+// parámetros: campos, expresiones, condición y versión.
+func (o *modificar) valoresSentencia() []interface{} {
+	var valores = make([]interface{}, 0, len(o.valores)+len(o.expresionesValores)+len(o.condicionValores)+1)
+	valores = append(valores, o.valores...)
+	valores = append(valores, o.expresionesValores...)
+	valores = append(valores, o.condicionValores...)
+	if o.versionCampo != "" {
+		valores = append(valores, o.versionValor)
+	}
+
+	return valores
+}
+
+// existeRegistro verifica si existen registros que cumplan con la condición
+// de la sentencia (sin tener en cuenta la versión).
+func (o *modificar) existeRegistro() (bool, error) {
+	if o.senSQLVersion == "" {
+		return false, errorNuevo().asignarMotivoVersionIncompatible(o.senSQLNombre)
+	}
+	sentencia, valores, err := expandirSentencia(o.senSQLVersion, o.condicionValores)
+	if err != nil {
+		return false, err
+	}
+
+	return existenRegistros(o.conexion, sentencia, valores)
+}
+
+// existenRegistros ejecuta la sentencia 'select count(*)' recibida e informa
+// si obtuvo algún registro.
+func existenRegistros(con Conexion, sentencia string, valores []interface{}) (bool, error) {
+	var fila = con.QueryRowContext(context.Background(), sentencia, valores...)
+
+	var cant int64
+	if err := fila.Scan(&cant); err != nil {
+		return false, resolverErrorMysql(err)
+	}
+
+	return cant > 0, nil
+}
+
+// -----------------------------------------------------------------------------
+
+type sentenciaPreparadaModificar struct {
+	bd        *BD
+	stmt      *sql.Stmt
+	sentencia string   // sentencia SQL con la que se creó la sentencia preparada
+	conexion  Conexion // conexión sobre la que se creó la sentencia preparada
+
+	cantValores int // cantidad de parámetros de la sentencia
+	valores     []interface{}
+
+	permitirCero bool // no considerar un error que ningún registro resulte afectado
+
+	sentenciaVersion string // sentencia que verifica la existencia del registro (ConVersion)
+}
+
+// Valores establece los valores de la sentencia en el orden de sus
+// parámetros: los campos a actualizar, la condición y, con ConVersion(), la
+// versión actual del registro.
+func (o *sentenciaPreparadaModificar) Valores(valores ...interface{}) *sentenciaPreparadaModificar {
+	o.valores = valores
+
+	return o
+}
+
+// SQLInterpolado devuelve la sentencia SQL con los valores incorporados como
+// literales, para registrarla o copiarla en una consola. Solo debe utilizarse
+// con ese fin: las sentencias se ejecutan siempre con parámetros.
+func (o *sentenciaPreparadaModificar) SQLInterpolado() (string, error) {
+	return o.bd.interpolarSentencia(o.sentencia, o.valores)
+}
+
+// PermitirCeroAfectados establece que no se considere un error que la
+// sentencia no afecte a ningún registro de la tabla.
+func (o *sentenciaPreparadaModificar) PermitirCeroAfectados() *sentenciaPreparadaModificar {
+	o.permitirCero = true
+
+	return o
+}
+
+// Ejecutar ejecuta la sentencia SQL.
+func (o *sentenciaPreparadaModificar) Ejecutar() error {
+	_, err := o.ejecutar(false)
+	return err
+}
+
+// EjecutarResultado ejecuta la sentencia SQL y devuelve el resultado de la
+// ejecución. Las advertencias del motor solo se obtienen cuando la sentencia
+// preparada pertenece a una transacción.
+func (o *sentenciaPreparadaModificar) EjecutarResultado() (Resultado, error) {
+	return o.ejecutar(true)
+}
+
+func (o *sentenciaPreparadaModificar) ejecutar(conAdvertencias bool) (Resultado, error) {
+	var errEjec = errorNuevo()
+	// verificar que los valores no se encuentren vacíos
+	if len(o.valores) == 0 {
+		return Resultado{}, errEjec.asignarMotivoValoresVacios()
+	}
+	// verificar que la cantidad de campos coincida con la cantidad de valores recibidos
+	if o.cantValores != len(o.valores) {
+		errEjec.asignarMotivoCamposValoresDiferenteCantidad()
+	}
+	// verificar que no existan valores que deban incorporarse a la sentencia
+	if requiereExpansion(o.valores) {
+		errEjec.asignarMotivoSentenciaPreparadaValorNoAdmitido()
+	}
+	if len(errEjec.mensajes) != 0 {
+		return Resultado{}, errEjec
+	}
+
+	res, err := ejecutarSentenciaPreparada(o.bd, o.stmt, o.sentencia, o.conexion, o.valores, conAdvertencias)
+	if err != nil {
+		return res, err
+	}
+	if res.RegistrosAfectados == 0 && o.sentenciaVersion != "" {
+		// verificar si el registro existe con los valores de la condición
+		// (anteriores al valor de la versión)
+		var fin = len(o.valores) - 1
+		var condicion = o.valores[fin-len(parametrosSQL(o.sentenciaVersion)) : fin]
+		existe, err := existenRegistros(o.conexion, o.sentenciaVersion, condicion)
+		if err != nil {
+			return res, err
+		}
+		if existe {
+			return res, errorNuevo().asignarMotivoConflictoDeVersion()
+		}
+	}
+	if res.RegistrosAfectados == 0 && !o.permitirCero {
+		return res, errorNuevo().asignarMotivoNingunRegistroAfectado()
+	}
+
+	return res, nil
+}
+
+// Cerrar cierra la sentencia preparada.
+func (o *sentenciaPreparadaModificar) Cerrar() error {
+	if o.stmt == nil {
+		// sentencia creada en el modo simulación
+		return nil
+	}
+	return resolverErrorMysql(o.stmt.Close())
+}
@@ -0,0 +1,162 @@
+package bdsql
+
+import (
+	"fmt"
+	"strings"
+)
+
+type modificarTabla struct {
+	bd *BD
+
+	tabla    string
+	acciones []accionTabla
+}
+
+// accionTabla representa una de las modificaciones de la sentencia
+// 'alter table'. Solo se completa uno de los campos 'columna',
+// 'claveForanea' o 'texto'.
+type accionTabla struct {
+	prefijo      string // 'add column', 'modify column', etc.
+	columna      *columna
+	claveForanea *claveForanea
+	texto        string
+	siExiste     bool // 'if exists' o 'if not exists' según la acción
+}
+
+// AgregarColumna agrega una columna a la tabla y la devuelve para
+// establecer su tipo y sus atributos:
+//
+//	mt := bd.ModificarTabla("personas")
+//	mt.AgregarColumna("telefono").Texto(30).Despues("apellidos")
+//	err := mt.Ejecutar()
+func (o *modificarTabla) AgregarColumna(nombre string) *columna {
+	var c = &columna{nombre: nombre}
+	o.acciones = append(o.acciones, accionTabla{prefijo: "add column", columna: c})
+
+	return c
+}
+
+// AgregarColumnaSiNoExiste agrega una columna solo si no existe en la tabla
+// ('add column if not exists'). Solo es admitida por Mariadb.
+func (o *modificarTabla) AgregarColumnaSiNoExiste(nombre string) *columna {
+	var c = &columna{nombre: nombre}
+	o.acciones = append(o.acciones, accionTabla{prefijo: "add column if not exists", columna: c, siExiste: true})
+
+	return c
+}
+
+// ModificarColumna redefine una columna existente y la devuelve para
+// establecer su nuevo tipo y sus atributos.
+func (o *modificarTabla) ModificarColumna(nombre string) *columna {
+	var c = &columna{nombre: nombre}
+	o.acciones = append(o.acciones, accionTabla{prefijo: "modify column", columna: c})
+
+	return c
+}
+
+// EliminarColumna elimina una columna de la tabla.
+func (o *modificarTabla) EliminarColumna(nombre string) *modificarTabla {
+	o.acciones = append(o.acciones, accionTabla{prefijo: "drop column", texto: nombre})
+	return o
+}
+
+// EliminarColumnaSiExiste elimina una columna solo si existe en la tabla
+// ('drop column if exists'). Solo es admitida por Mariadb.
+func (o *modificarTabla) EliminarColumnaSiExiste(nombre string) *modificarTabla {
+	o.acciones = append(o.acciones, accionTabla{prefijo: "drop column if exists", texto: nombre, siExiste: true})
+	return o
+}
+
+// RenombrarColumna cambia el nombre de una columna sin modificar su
+// definición.
+func (o *modificarTabla) RenombrarColumna(anterior, nuevo string) *modificarTabla {
+	o.acciones = append(o.acciones, accionTabla{prefijo: "rename column", texto: anterior + " to " + nuevo})
+	return o
+}
+
+// AgregarIndice agrega un índice a la tabla.
+func (o *modificarTabla) AgregarIndice(nombre string, columnas ...string) *modificarTabla {
+	o.acciones = append(o.acciones, accionTabla{prefijo: "add index", texto: fmt.Sprintf("%v (%v)", nombre, strings.Join(columnas, ", "))})
+	return o
+}
+
+// AgregarIndiceUnico agrega un índice único a la tabla.
+func (o *modificarTabla) AgregarIndiceUnico(nombre string, columnas ...string) *modificarTabla {
+	o.acciones = append(o.acciones, accionTabla{prefijo: "add unique index", texto: fmt.Sprintf("%v (%v)", nombre, strings.Join(columnas, ", "))})
+	return o
+}
+
+// EliminarIndice elimina un índice de la tabla.
+func (o *modificarTabla) EliminarIndice(nombre string) *modificarTabla {
+	o.acciones = append(o.acciones, accionTabla{prefijo: "drop index", texto: nombre})
+	return o
+}
+
+// AgregarClaveForanea agrega una clave foránea a la tabla compuesta por las
+// columnas recibidas, y la devuelve para establecer la tabla referenciada.
+func (o *modificarTabla) AgregarClaveForanea(columnas ...string) *claveForanea {
+	var cf = &claveForanea{tabla: o.tabla, columnas: columnas}
+	o.acciones = append(o.acciones, accionTabla{prefijo: "add", claveForanea: cf})
+
+	return cf
+}
+
+// EliminarClaveForanea elimina una clave foránea de la tabla.
+func (o *modificarTabla) EliminarClaveForanea(nombre string) *modificarTabla {
+	o.acciones = append(o.acciones, accionTabla{prefijo: "drop foreign key", texto: nombre})
+	return o
+}
+
+// SQL devuelve la sentencia SQL.
+func (o *modificarTabla) SQL() (string, error) {
+	return o.generarSQL()
+}
+
+// Ejecutar ejecuta la sentencia SQL.
+func (o *modificarTabla) Ejecutar() error {
+	var sentencia, err = o.generarSQL()
+	if err != nil {
+		return err
+	}
+	_, err = ejecutarSentencia(o.bd, o.bd.db, sentencia, nil, false)
+
+	return err
+}
+
+func (o *modificarTabla) generarSQL() (string, error) {
+	var err = errorNuevo()
+	// verificar que el nombre de la tabla no se encuentre vacía
+	if o.tabla == "" {
+		err.asignarMotivoNombreDeTablaVacia()
+	}
+	// verificar que existan modificaciones
+	if len(o.acciones) == 0 {
+		err.asignarMotivoNombresDeCamposVacios()
+	}
+	if len(err.mensajes) != 0 {
+		return "", err
+	}
+
+	var dialecto = o.bd.obtenerDialecto()
+	var acciones []string
+	for _, a := range o.acciones {
+		if a.siExiste && !dialecto.admiteSiExisteEnModificaciones() {
+			return "", errorNuevo().asignarMotivoNoAdmitidoPorDialecto("'" + a.prefijo + "'")
+		}
+
+		var definicion = a.texto
+		var err error
+		switch {
+		case a.columna != nil:
+			definicion, err = a.columna.generarSQL()
+		case a.claveForanea != nil:
+			definicion, err = a.claveForanea.generarSQL()
+		}
+		if err != nil {
+			return "", err
+		}
+		acciones = append(acciones, a.prefijo+" "+definicion)
+	}
+
+	return fmt.Sprintf("alter table %v %v;", o.tabla, strings.Join(acciones, ", ")), nil
+}
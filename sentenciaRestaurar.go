@@ -0,0 +1,189 @@
+package bdsql
+
+import (
+	"fmt"
+)
+
+type restaurar struct {
+	bd       *BD
+	conexion Conexion // conexión sobre la que se ejecuta la sentencia: el pool o una transacción
+
+	tabla string
+
+	condicion        string
+	condicionValores []interface{}
+
+	limite int
+
+	permitirCero bool // no considerar un error que ningún registro resulte afectado
+
+	err error // error producido al establecer los elementos de la sentencia
+
+	senSQLExiste bool
+	senSQLNombre string
+	senSQL       string
+}
+
+// nuevoRestaurar crea la sentencia sobre la conexión recibida. Si la sentencia fue
+// generada previamente con el mismo nombre, se reutiliza.
+func nuevoRestaurar(bd *BD, conexion Conexion, nombre string) *restaurar {
+	var o = &restaurar{bd: bd, conexion: conexion}
+	if nombre != "-" {
+		o.senSQLNombre = nombre
+		o.senSQL, o.senSQLExiste = o.bd.obtenerSentenciaSQL(nombre)
+	}
+
+	return o
+}
+
+// Tabla establece el nombre de la tabla donde se restaurarán los registros.
+// La tabla debe haber sido registrada con BD.BorradoLogico().
+func (o *restaurar) Tabla(tabla string) *restaurar {
+	if o.senSQLExiste {
+		return o
+	}
+
+	o.tabla = tabla
+	return o
+}
+
+// Condicion implementa la cláusula 'where' de la sentencia.
+// La condición puede contener parámetros nombrados (':nombre') cuyos valores
+// se reciben en un mapa, una estructura o con sql.Named(); '@nombre' es una
+// variable de sesión y no se reemplaza.
+// También puede recibir un predicado (Igual, Entre, Y, O, etc.).
+func (o *restaurar) Condicion(condicion interface{}, valores ...interface{}) *restaurar {
+	texto, valores, err := resolverCondicion(condicion, valores, false)
+	if err != nil {
+		o.err = err
+		return o
+	}
+	if !o.senSQLExiste {
+		o.condicion = texto
+	}
+	o.condicionValores = valores
+
+	return o
+}
+
+// Limitar implementa la cláusula 'limit' de la sentencia.
+func (o *restaurar) Limitar(limite int) *restaurar {
+	o.limite = limite
+
+	return o
+}
+
+// PermitirCeroAfectados establece que no se considere un error que la
+// sentencia no afecte a ningún registro de la tabla.
+func (o *restaurar) PermitirCeroAfectados() *restaurar {
+	o.permitirCero = true
+
+	return o
+}
+
+// SQL devuelve la sentencia SQL.
+func (o *restaurar) SQL() (string, error) {
+	var sentencia, err = o.generarSQL()
+	if err != nil {
+		return "", err
+	}
+	sentencia, _, err = expandirSentencia(sentencia, o.condicionValores)
+
+	return sentencia, err
+}
+
+// SQLInterpolado devuelve la sentencia SQL con los valores incorporados como
+// literales, para registrarla o copiarla en una consola. Solo debe utilizarse
+// con ese fin: las sentencias se ejecutan siempre con parámetros.
+func (o *restaurar) SQLInterpolado() (string, error) {
+	var sentencia, err = o.generarSQL()
+	if err != nil {
+		return "", err
+	}
+	sentencia, valores, err := expandirSentencia(sentencia, o.condicionValores)
+	if err != nil {
+		return "", err
+	}
+
+	return o.bd.interpolarSentencia(sentencia, valores)
+}
+
+// Ejecutar ejecuta la sentencia SQL.
+func (o *restaurar) Ejecutar() error {
+	_, err := o.ejecutar(false)
+	return err
+}
+
+// EjecutarResultado ejecuta la sentencia SQL y devuelve el resultado de la
+// ejecución: registros afectados y advertencias del motor.
+func (o *restaurar) EjecutarResultado() (Resultado, error) {
+	return o.ejecutar(true)
+}
+
+func (o *restaurar) ejecutar(conAdvertencias bool) (Resultado, error) {
+	var sentencia, err = o.generarSQL()
+	if err != nil {
+		return Resultado{}, err
+	}
+
+	// verificar que los valores de la condición no se encuentren vacíos
+	if len(o.condicionValores) == 0 {
+		return Resultado{}, errorNuevo().asignarMotivoValoresCondicionVacia()
+	}
+
+	sentencia, valores, err := expandirSentencia(sentencia, o.condicionValores)
+	if err != nil {
+		return Resultado{}, err
+	}
+
+	res, err := ejecutarSentencia(o.bd, o.conexion, sentencia, valores, conAdvertencias)
+	if err != nil {
+		return res, err
+	}
+	if res.RegistrosAfectados == 0 && !o.permitirCero {
+		return res, errorNuevo().asignarMotivoNingunRegistroAfectado()
+	}
+
+	return res, nil
+}
+
+func (o *restaurar) generarSQL() (string, error) {
+	if o.err != nil {
+		return "", o.err
+	}
+	if o.senSQLExiste {
+		return o.senSQL, nil
+	}
+
+	var err = errorNuevo()
+	// verificar que el nombre de la tabla no se encuentre vacía
+	if o.tabla == "" {
+		err.asignarMotivoNombreDeTablaVacia()
+	}
+	// verificar que la condición no se encuentre vacía
+	// no se permite realizar restauraciones sin condición
+	if o.condicion == "" {
+		err.asignarMotivoCondicionVacia()
+	}
+	if len(err.mensajes) != 0 {
+		return "", err
+	}
+
+	// verificar que la tabla utilice borrado lógico
+	campo, ok := o.bd.campoBorradoLogico(o.tabla)
+	if !ok {
+		return "", errorNuevo().asignarMotivoTablaSinBorradoLogico()
+	}
+
+	// sentencia con cláusula where
+	var sentencia = fmt.Sprintf("update %v set %v = null where (%v) and %v is not null", o.tabla, campo, o.condicion, campo)
+	// limit
+	if o.limite > 0 {
+		sentencia += fmt.Sprintf(" limit %v", o.limite)
+	}
+
+	sentencia += ";"
+	o.bd.guardarSentenciaSQL(o.senSQLNombre, sentencia)
+
+	return sentencia, nil
+}
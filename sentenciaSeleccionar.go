@@ -1,825 +1,1566 @@
-package bdsql
-
-import (
-	"fmt"
-	"reflect"
-	"strconv"
-	"strings"
-	"time"
-
-	"database/sql"
-)
-
-type seleccionar struct {
-	bd *BD
-	tx *sql.Tx
-
-	tabla            string
-	campos           []string
-	condicion        string
-	condicionValores []interface{}
-	ordenadoPor      []string
-
-	agruparPor        []string
-	teniendoCondicion string
-	teniendoValores   []interface{}
-
-	limite int
-	salto  int
-
-	objeto interface{} // puntero de slice de objeto para el método Resultado().
-
-	juntaInternaTabla       []string
-	juntaInternaCondicion   []string
-	juntaIzquierdaTabla     []string
-	juntaIzquierdaCondicion []string
-	juntaDerechaTabla       []string
-	juntaDerechaCondicion   []string
-	juntaExternaTabla       []string
-	juntaExternaCondicion   []string
-
-	senSQLExiste bool
-	senSQLNombre string
-	senSQL       string
-}
-
-// Tabla establece el nombre de la tabla a seleccionar.
-func (o *seleccionar) Tabla(tabla string) *seleccionar {
-	if o.senSQLExiste {
-		return o
-	}
-
-	o.tabla = tabla
-	return o
-}
-
-// Campos establece los nombres de campos a seleccionar.
-func (o *seleccionar) Campos(campos ...string) *seleccionar {
-	o.campos = campos
-	return o
-}
-
-// Condicion implementa la cláusula 'where' de la sentencia 'select'.
-func (o *seleccionar) Condicion(condicion string, valores ...interface{}) *seleccionar {
-	if !o.senSQLExiste {
-		o.condicion = condicion
-	}
-	o.condicionValores = valores
-
-	return o
-}
-
-// OrdenarPor implementa a la cláusula 'order by' de la sentencia 'select'.
-func (o *seleccionar) OrdenarPor(valores ...string) *seleccionar {
-	if !o.senSQLExiste {
-		o.ordenadoPor = valores
-	}
-
-	return o
-}
-
-// AgruparPor implementa a la cláusula 'group by' de la sentencia 'select'.
-func (o *seleccionar) AgruparPor(valores ...string) *seleccionar {
-	if !o.senSQLExiste {
-		o.agruparPor = valores
-	}
-	return o
-}
-
-// Teniendo implementa la cláusula 'having' de la sentencia 'select'.
-func (o *seleccionar) Teniendo(condicion string, valores ...interface{}) *seleccionar {
-	if !o.senSQLExiste {
-		o.teniendoCondicion = condicion
-	}
-	o.teniendoValores = valores
-
-	return o
-}
-
-// Limitar implementa la cláusula 'limit' de la sentencia 'select'.
-func (o *seleccionar) Limitar(limite int) *seleccionar {
-	o.limite = limite
-
-	return o
-}
-
-// Saltar implementa la cláusula 'offset' de la sentencia 'select'.
-func (o *seleccionar) Saltar(salto int) *seleccionar {
-	o.salto = salto
-
-	return o
-}
-
-// Juntar implementa la cláusula 'inner join' de la sentencia 'select'.
-func (o *seleccionar) JuntarCon(tabla, condicion string) *seleccionar {
-	if !o.senSQLExiste {
-		o.juntaInternaTabla = append(o.juntaInternaTabla, tabla)
-		o.juntaInternaCondicion = append(o.juntaInternaCondicion, condicion)
-	}
-	return o
-}
-
-// JuntarIzquierda implementa la cláusula 'left join' de la sentencia 'select'.
-func (o *seleccionar) JuntarIzquierda(tabla, condicion string) *seleccionar {
-	if !o.senSQLExiste {
-		o.juntaIzquierdaTabla = append(o.juntaIzquierdaTabla, tabla)
-		o.juntaIzquierdaCondicion = append(o.juntaIzquierdaCondicion, condicion)
-	}
-	return o
-}
-
-// JuntarDerecha implementa la cláusula 'right join' de la sentencia 'select'.
-func (o *seleccionar) JuntarDerecha(tabla, condicion string) *seleccionar {
-	if !o.senSQLExiste {
-		o.juntaDerechaTabla = append(o.juntaDerechaTabla, tabla)
-		o.juntaDerechaCondicion = append(o.juntaDerechaCondicion, condicion)
-	}
-	return o
-}
-
-// JuntarExterior implementa la cláusula 'outer join' de la sentencia 'select'.
-func (o *seleccionar) JuntarExterior(tabla, condicion string) *seleccionar {
-	if !o.senSQLExiste {
-		o.juntaExternaTabla = append(o.juntaExternaTabla, tabla)
-		o.juntaExternaCondicion = append(o.juntaExternaCondicion, condicion)
-	}
-	return o
-}
-
-// Resultado recibe el objeto donde se almacena el resultado de la consulta.
-// Debe ser un puntero de slice de una estructura.
-//	Ejemplo:
-//	var elementos = []struct {
-//		ID            int64  `bdsql:"id"`
-//		Nombre        string `bdsql:"nombre"`
-//		EsActivo      bool   `bdsql:"es_activo"`
-//		Observaciones string `bdsql:"observaciones"`
-//	}{}
-//	var cant, err = bd.Seleccionar("consulta").
-//					Tabla("elementos").
-//					Campos("*").
-//					Resultado(&elementos).
-//					Ejecutar()
-//	if err != nil {
-//		fmt.Println(err)
-//		return err
-//	}
-//	fmt.Println(cant, elementos)
-//
-func (o *seleccionar) Resultado(objeto interface{}) *seleccionar {
-	o.objeto = objeto
-	return o
-}
-
-// SQL devuelve la sentencia SQL.
-func (o *seleccionar) SQL() (string, error) {
-	return o.generarSQL()
-}
-
-// Ejecutar ejecuta la sentencia SQL.
-func (o *seleccionar) Ejecutar() (int, error) {
-	var sentencia, err = o.generarSQL()
-	if err != nil {
-		return 0, err
-	}
-
-	// validar que el objeto de resultado, sea un puntero de slice de estructura
-	if ok := reflect.TypeOf(o.objeto).Kind() == reflect.Ptr && reflect.TypeOf(o.objeto).Elem().Kind() == reflect.Slice && reflect.TypeOf(o.objeto).Elem().Elem().Kind() == reflect.Struct; !ok {
-		return 0, errorNuevo().asignarMotivoSeleccionarPunteroDeSlice()
-	}
-
-	// slice de parámetros de la sentencia sql a ejecutar
-	var parametros []interface{}
-	// where
-	if o.condicion != "" {
-		parametros = append(parametros, o.condicionValores...)
-	}
-	// having
-	if o.teniendoCondicion != "" {
-		parametros = append(parametros, o.teniendoValores...)
-	}
-
-	// var err error
-	var filas *sql.Rows
-	if o.tx == nil {
-		filas, err = o.bd.db.Query(sentencia, parametros...)
-	} else {
-		filas, err = o.tx.Query(sentencia, parametros...)
-	}
-	if err != nil {
-		return 0, resolverErrorMysql(err)
-	}
-	defer filas.Close()
-
-	cant, err := asignarAObjeto(filas, o.objeto)
-	if err != nil {
-		return 0, err
-	}
-
-	return cant, nil
-}
-
-func (o *seleccionar) generarSQL() (string, error) {
-	if o.senSQLExiste {
-		return o.senSQL, nil
-	}
-
-	var err = errorNuevo()
-	// verificar que el nombre de la tabla no se encuentre vacía
-	if o.tabla == "" {
-		err.asignarMotivoNombreDeTablaVacia()
-	}
-	// verificar que los nombres de campos no se encuentren vacíos
-	if len(o.campos) == 0 {
-		err.asignarMotivoNombresDeCamposVacios()
-	}
-	if len(err.mensajes) != 0 {
-		return "", err
-	}
-	// sentencia
-	sentencia := fmt.Sprintf("select %v from %v", strings.Join(o.campos, ", "), o.tabla)
-	// inner join
-	for i := 0; i < len(o.juntaInternaTabla); i++ {
-		sentencia += fmt.Sprintf(" inner join %v on %v", o.juntaInternaTabla[i], o.juntaInternaCondicion[i])
-	}
-	// left join
-	for i := 0; i < len(o.juntaIzquierdaTabla); i++ {
-		sentencia += fmt.Sprintf(" left join %v on %v", o.juntaIzquierdaTabla[i], o.juntaIzquierdaCondicion[i])
-	}
-	// right join
-	for i := 0; i < len(o.juntaDerechaTabla); i++ {
-		sentencia += fmt.Sprintf(" right join %v on %v", o.juntaDerechaTabla[i], o.juntaDerechaCondicion[i])
-	}
-	// outer join
-	for i := 0; i < len(o.juntaExternaTabla); i++ {
-		sentencia += fmt.Sprintf(" outer join %v on %v", o.juntaExternaTabla[i], o.juntaExternaCondicion[i])
-	}
-	// where
-	if o.condicion != "" {
-		sentencia += fmt.Sprintf(" where %v", o.condicion)
-	}
-	// group by
-	if len(o.agruparPor) > 0 {
-		sentencia += fmt.Sprintf(" group by %v", strings.Join(o.agruparPor, ", "))
-	}
-	// having
-	if o.teniendoCondicion != "" {
-		sentencia += fmt.Sprintf(" having %v", o.teniendoCondicion)
-	}
-	// order by
-	if len(o.ordenadoPor) > 0 {
-		sentencia += fmt.Sprintf(" order by %v", strings.Join(o.ordenadoPor, ", "))
-	}
-	// limit
-	if o.limite > 0 {
-		sentencia += fmt.Sprintf(" limit %v", o.limite)
-	}
-	// offset
-	if o.salto > 0 {
-		if o.limite == 0 {
-			sentencia += fmt.Sprintf(" limit %v", 2100000000)
-		}
-		sentencia += fmt.Sprintf(" offset %v", o.salto)
-	}
-
-	return fmt.Sprintf("%v;", sentencia), nil
-}
-
-func asignarAObjeto(filas *sql.Rows, objeto interface{}) (int, error) {
-	// nombres de campos del resultado obtenido de la base de datos.
-	camposFila, err := filas.Columns()
-	if err != nil {
-		return 0, resolverErrorMysql(err)
-	}
-
-	// obtener la lista de campos de la estructura que podrían ser asignados
-	// desde la consulta obtenida.
-	estructura := reflect.TypeOf(objeto).Elem().Elem()
-
-	// mapa de estructura del objeto:
-	// la clave es el valor de la etiqueta.
-	// el valor es el nombre del campo de la estructura.
-	camposEstructura := make(map[string]string)
-
-	var encontrados int
-	for i := 0; i < estructura.NumField(); i++ {
-		campo := estructura.Field(i)
-		campoTabla := strings.Trim(campo.Tag.Get("bdsql"), " ")
-
-		switch campoTabla {
-		case "-":
-			// no asignar el valor
-			continue
-		case "":
-			// en caso que el campo de la estructura no tenga asignado el
-			// tag "bdsql", se asume que el nombre del campo de la la consulta
-			// SQL, es el mismo (en minúsculas) que el nombre de campo de la
-			// estructura.
-			camposEstructura[strings.ToLower(campo.Name)] = campo.Name
-		default:
-			// en caso que el campo de la estructura tenga asignado el
-			// tag "bdsql", se toma el tag para obtener el dato de la
-			// consulta SQL.
-			camposEstructura[campoTabla] = campo.Name
-		}
-
-		encontrados++
-	}
-	// podría pasar (raramente) que todos los campos de la estructura contengan
-	// el tag `bdsql:"-"`. En ese caso, la estrucutra no permite que ninguno de
-	// sus campos sean asignables por los valores recibidos de la consulta SQL.
-	if encontrados == 0 {
-		return 0, errorNuevo().asignarMotivoSeleccionarCamposSinRelacion()
-	}
-
-	// mapa de funciones:
-	// se crea un slice el cuál mantiene la función que completa cada campo
-	// de la estructura.
-	funciones := make(map[string]func(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error), estructura.NumField())
-
-	// verificar qe todos los campos de la consulta obtenida, puedan ser
-	// ingresados en el objeto recibido.
-	var camposFaltantes []string
-	estructuraNueva := reflect.New(estructura).Elem()
-	for _, campoFila := range camposFila {
-		campoEstructura, ok := camposEstructura[campoFila]
-		if !ok {
-			camposFaltantes = append(camposFaltantes, campoFila)
-			continue
-		}
-
-		// llenar el slice de funciones según cada tipo de campo de la estructura.
-		switch estructuraNueva.FieldByName(campoEstructura).Kind() {
-		case reflect.Int:
-			funciones[campoEstructura] = valori
-		case reflect.Int8:
-			funciones[campoEstructura] = valori8
-		case reflect.Int16:
-			funciones[campoEstructura] = valori16
-		case reflect.Int32:
-			funciones[campoEstructura] = valori32
-		case reflect.Int64:
-			funciones[campoEstructura] = valori64
-		case reflect.Uint:
-			funciones[campoEstructura] = valorui
-		case reflect.Uint8:
-			funciones[campoEstructura] = valorui8
-		case reflect.Uint16:
-			funciones[campoEstructura] = valorui16
-		case reflect.Uint32:
-			funciones[campoEstructura] = valorui32
-		case reflect.Uint64:
-			funciones[campoEstructura] = valorui64
-		case reflect.Float32:
-			funciones[campoEstructura] = valorf32
-		case reflect.Float64:
-			funciones[campoEstructura] = valorf64
-		case reflect.Bool:
-			funciones[campoEstructura] = valorbool
-		case reflect.String:
-			funciones[campoEstructura] = valorstring
-		case reflect.Struct:
-			if estructuraNueva.FieldByName(campoEstructura).Type().String() == "time.Time" {
-				funciones[campoEstructura] = valorFecha
-			} else {
-				return 0, errorNuevo().asignarMotivoSeleccionarContieneEstructura()
-			}
-		default:
-			return 0, errorNuevo().asignarMotivoSeleccionarTipoDeCampoIncorrecto()
-		}
-	}
-	if len(camposFaltantes) > 0 {
-		return 0, errorNuevo().asignarMotivoSeleccionarCamposFaltantes(strings.Join(camposFaltantes, ","))
-	}
-
-	// obtener los valores de los campos de la base de datos.
-	var valores = make([]interface{}, len(camposFila))
-	for i := range valores {
-		var ii interface{}
-		valores[i] = &ii
-	}
-
-	// sabiendo que el objeto es un puntero de slice de una estructura se
-	// asigna un elemento para ir incorporando las estructuras.
-	sliceDeObjetos := reflect.ValueOf(objeto).Elem()
-
-	// recorrer todas las filas e insertarlas en el objeto.
-	var cant int
-	for filas.Next() {
-		cant++
-
-		if err := filas.Scan(valores...); err != nil {
-			return 0, errorNuevo().asignarOrigen(err).asignarMotivoSeleccionarLecturaDeCampos()
-		}
-
-		for i, campoFila := range camposFila {
-			var valorCrudo = *(valores[i].(*interface{}))
-			var tipoCrudo = reflect.TypeOf(valorCrudo)
-
-			campoEstructura, ok := camposEstructura[campoFila]
-			if !ok {
-				return 0, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos(fmt.Sprintf("Inexistente: %v, Campo: %v", err, campoFila))
-			}
-
-			valor, err := funciones[campoEstructura](valorCrudo, tipoCrudo)
-			if err != nil {
-				return 0, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos(fmt.Sprintf("Error: %v, Campo: %v", err, campoFila))
-			}
-			estructuraNueva.FieldByName(campoEstructura).Set(valor)
-		}
-
-		sliceDeObjetos.Set(reflect.Append(sliceDeObjetos, estructuraNueva))
-	}
-
-	return cant, nil
-}
-
-// ---- Funciones de asignación de campos de la estructura ---------------------
-
-func valori(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
-	var vacio reflect.Value
-	var v int
-
-	if tipoCrudo != nil {
-		if tipoCrudo.String() == "[]uint8" {
-			s, ok := valorCrudo.([]uint8)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
-			}
-			vTemp, err := strconv.Atoi(string(s))
-			if err != nil {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo int")
-			}
-			v = vTemp
-		} else {
-			vTemp, ok := valorCrudo.(int64)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo int64")
-			}
-			v = int(vTemp)
-		}
-	}
-
-	return reflect.ValueOf(v), nil
-}
-
-func valori8(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
-	var vacio reflect.Value
-	var v int8
-
-	if tipoCrudo != nil {
-		if tipoCrudo.String() == "[]uint8" {
-			s, ok := valorCrudo.([]uint8)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
-			}
-			vTemp, err := strconv.ParseInt(string(s), 10, 8)
-			if err != nil {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo int8")
-			}
-			v = int8(vTemp)
-		} else {
-			vTemp, ok := valorCrudo.(int64)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo int64")
-			}
-			v = int8(vTemp)
-		}
-	}
-
-	return reflect.ValueOf(v), nil
-}
-
-func valori16(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
-	var vacio reflect.Value
-	var v int16
-
-	if tipoCrudo != nil {
-		if tipoCrudo.String() == "[]uint8" {
-			s, ok := valorCrudo.([]uint8)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
-			}
-			vTemp, err := strconv.ParseInt(string(s), 10, 16)
-			if err != nil {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo int16")
-			}
-			v = int16(vTemp)
-		} else {
-			vTemp, ok := valorCrudo.(int64)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo int64")
-			}
-			v = int16(vTemp)
-		}
-	}
-
-	return reflect.ValueOf(v), nil
-}
-
-func valori32(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
-	var vacio reflect.Value
-	var v int32
-
-	if tipoCrudo != nil {
-		if tipoCrudo.String() == "[]uint8" {
-			s, ok := valorCrudo.([]uint8)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
-			}
-			vTemp, err := strconv.ParseInt(string(s), 10, 32)
-			if err != nil {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo int32")
-			}
-			v = int32(vTemp)
-		} else {
-			vTemp, ok := valorCrudo.(int64)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo int64")
-			}
-			v = int32(vTemp)
-		}
-	}
-
-	return reflect.ValueOf(v), nil
-}
-
-func valori64(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
-	var vacio reflect.Value
-	var v int64
-
-	if tipoCrudo != nil {
-		if tipoCrudo.String() == "[]uint8" {
-			s, ok := valorCrudo.([]uint8)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
-			}
-			vTemp, err := strconv.ParseInt(string(s), 10, 64)
-			if err != nil {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo int64")
-			}
-			v = vTemp
-		} else {
-			vTemp, ok := valorCrudo.(int64)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo int64")
-			}
-			v = vTemp
-		}
-	}
-
-	return reflect.ValueOf(v), nil
-}
-
-func valorui(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
-	var vacio reflect.Value
-	var v uint
-
-	if tipoCrudo != nil {
-		if tipoCrudo.String() == "[]uint8" {
-			s, ok := valorCrudo.([]uint8)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
-			}
-			vTemp, err := strconv.ParseUint(string(s), 10, 64)
-			if err != nil {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo uint")
-			}
-			v = uint(vTemp)
-		} else {
-			vTemp, ok := valorCrudo.(uint)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo uint")
-			}
-			v = uint(vTemp)
-		}
-	}
-
-	return reflect.ValueOf(v), nil
-}
-
-func valorui8(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
-	var vacio reflect.Value
-	var v uint8
-
-	if tipoCrudo != nil {
-		if tipoCrudo.String() == "[]uint8" {
-			s, ok := valorCrudo.([]uint8)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
-			}
-			vTemp, err := strconv.ParseUint(string(s), 10, 8)
-			if err != nil {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo uint8")
-			}
-			v = uint8(vTemp)
-		} else {
-			vTemp, ok := valorCrudo.(uint8)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo uint8")
-			}
-			v = vTemp
-		}
-	}
-
-	return reflect.ValueOf(v), nil
-}
-
-func valorui16(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
-	var vacio reflect.Value
-	var v uint16
-
-	if tipoCrudo != nil {
-		if tipoCrudo.String() == "[]uint8" {
-			s, ok := valorCrudo.([]uint8)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
-			}
-			vTemp, err := strconv.ParseUint(string(s), 10, 16)
-			if err != nil {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo uint16")
-			}
-			v = uint16(vTemp)
-		} else {
-			vTemp, ok := valorCrudo.(uint16)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo uint16")
-			}
-			v = vTemp
-		}
-	}
-
-	return reflect.ValueOf(v), nil
-}
-
-func valorui32(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
-	var vacio reflect.Value
-	var v uint32
-
-	if tipoCrudo != nil {
-		if tipoCrudo.String() == "[]uint8" {
-			s, ok := valorCrudo.([]uint8)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
-			}
-			vTemp, err := strconv.ParseUint(string(s), 10, 32)
-			if err != nil {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo uint32")
-			}
-			v = uint32(vTemp)
-		} else {
-			vTemp, ok := valorCrudo.(uint32)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo uint32")
-			}
-			v = vTemp
-		}
-	}
-
-	return reflect.ValueOf(v), nil
-}
-
-func valorui64(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
-	var vacio reflect.Value
-	var v uint64
-
-	if tipoCrudo != nil {
-		if tipoCrudo.String() == "[]uint8" {
-			s, ok := valorCrudo.([]uint8)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
-			}
-			vTemp, err := strconv.ParseUint(string(s), 10, 64)
-			if err != nil {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo uint64")
-			}
-			v = vTemp
-		} else {
-			vTemp, ok := valorCrudo.(uint64)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo uint64")
-			}
-			v = vTemp
-		}
-	}
-
-	return reflect.ValueOf(v), nil
-}
-
-func valorf32(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
-	var vacio reflect.Value
-	var v float32
-
-	if tipoCrudo != nil {
-		if tipoCrudo.String() == "[]uint8" {
-			s, ok := valorCrudo.([]uint8)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
-			}
-			vTemp, err := strconv.ParseFloat(string(s), 32)
-			if err != nil {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo float32")
-			}
-			v = float32(vTemp)
-		} else {
-			vTemp, ok := valorCrudo.(float32)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo float32")
-			}
-			v = float32(vTemp)
-		}
-	}
-
-	return reflect.ValueOf(v), nil
-}
-
-func valorf64(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
-	var vacio reflect.Value
-	var v float64
-
-	if tipoCrudo != nil {
-		if tipoCrudo.String() == "[]uint8" {
-			s, ok := valorCrudo.([]uint8)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
-			}
-			vTemp, err := strconv.ParseFloat(string(s), 64)
-			if err != nil {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo float64")
-			}
-			v = vTemp
-		} else {
-			vTemp, ok := valorCrudo.(float64)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo float64")
-			}
-			v = vTemp
-		}
-	}
-
-	return reflect.ValueOf(v), nil
-}
-
-func valorbool(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
-	var vacio reflect.Value
-	var v bool
-
-	if tipoCrudo != nil {
-		if tipoCrudo.String() == "[]uint8" {
-			s, ok := valorCrudo.([]uint8)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
-			}
-			vTemp, err := strconv.ParseBool(string(s))
-			if err != nil {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo bool")
-			}
-			v = vTemp
-		} else {
-			switch valorCrudo.(type) {
-			case bool:
-				v = valorCrudo.(bool)
-			case int64:
-				v = valorCrudo == int64(1)
-			default:
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible asignar a tipo bool")
-			}
-		}
-	}
-
-	return reflect.ValueOf(v), nil
-}
-
-func valorstring(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
-	var vacio reflect.Value
-	var v string
-
-	if tipoCrudo != nil {
-		if tipoCrudo.String() == "[]uint8" {
-			s, ok := valorCrudo.([]uint8)
-			if !ok {
-				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
-			}
-			v = fmt.Sprintf("%s", s)
-		} else {
-			v = fmt.Sprintf("%s", valorCrudo)
-		}
-	}
-
-	return reflect.ValueOf(v), nil
-}
-
-func valorFecha(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
-	var vacio reflect.Value
-	var v time.Time = time.Time{}
-
-	if valorCrudo != nil {
-		fh, ok := valorCrudo.(time.Time)
-		if !ok {
-			return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo time.Time")
-		}
-		v = fh
-	}
-
-	return reflect.ValueOf(v), nil
-}
+package bdsql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"database/sql"
+)
+
+type seleccionar struct {
+	bd       *BD
+	conexion Conexion // conexión sobre la que se ejecuta la sentencia: el pool o una transacción
+
+	tabla            string
+	tablaValores     []interface{} // subconsulta de la tabla derivada
+	campos           []string
+	condicion        string
+	condicionValores []interface{}
+	ordenadoPor      []string
+
+	agruparPor        []string
+	teniendoCondicion string
+	teniendoValores   []interface{}
+
+	limite int
+	salto  int
+
+	cursor          string        // cursor de paginación recibido en DespuesDe()
+	cursorCondicion string        // condición obtenida del cursor de paginación
+	cursorValores   []interface{} // valores de la condición del cursor de paginación
+
+	camposSubconsultas []string      // campos obtenidos de subconsultas escalares
+	camposValores      []interface{} // subconsultas de los campos
+
+	con          []string      // expresiones de tabla comunes de la cláusula 'with'
+	conValores   []interface{} // subconsultas de la cláusula 'with'
+	conRecursivo bool          // 'with recursive'
+
+	uniones        []string      // 'union' / 'union all' de cada sentencia unida
+	unionesValores []interface{} // sentencias unidas
+
+	distinto     bool              // 'select distinct'
+	juntaDirecta bool              // 'select straight_join'
+	sugerencias  []string          // sugerencias al optimizador ('/*+ ... */')
+	indices      map[string]string // sugerencias de índices por tabla ('use index' / 'force index')
+	ventanas     []string          // ventanas con nombre de la cláusula 'window'
+
+	objeto interface{} // puntero de slice de objeto para el método Resultado().
+
+	eliminados int // tratamiento de los registros con borrado lógico (excluir, incluir o solo eliminados)
+
+	bloqueo       int // bloqueo de los registros obtenidos (para modificar o compartir)
+	bloqueoEspera int // espera de los registros bloqueados por otra transacción
+
+	juntas []junta // juntas en el orden en que fueron establecidas
+
+	err error // error producido al establecer los elementos de la sentencia
+
+	senSQLExiste bool
+	senSQLNombre string
+	senSQL       string
+}
+
+// nuevoSeleccionar crea la sentencia sobre la conexión recibida. Si la sentencia fue
+// generada previamente con el mismo nombre, se reutiliza.
+func nuevoSeleccionar(bd *BD, conexion Conexion, nombre string) *seleccionar {
+	var o = &seleccionar{bd: bd, conexion: conexion}
+	if nombre != "-" {
+		o.senSQLNombre = nombre
+		o.senSQL, o.senSQLExiste = o.bd.obtenerSentenciaSQL(nombre)
+	}
+
+	return o
+}
+
+// Tabla establece el nombre de la tabla a seleccionar.
+func (o *seleccionar) Tabla(tabla string) *seleccionar {
+	if o.senSQLExiste {
+		return o
+	}
+
+	o.tabla = tabla
+	return o
+}
+
+// TablaSubconsulta establece una subconsulta como tabla derivada de la
+// sentencia: 'select ... from (subconsulta) as alias'. Los valores de la
+// subconsulta se incorporan a los valores de la sentencia.
+func (o *seleccionar) TablaSubconsulta(subconsulta *seleccionar, alias string) *seleccionar {
+	if !o.senSQLExiste {
+		o.tabla = fmt.Sprintf("(?) as %v", alias)
+	}
+	o.tablaValores = []interface{}{subconsulta}
+
+	return o
+}
+
+// CampoSubconsulta agrega como campo a seleccionar el resultado de una
+// subconsulta escalar: 'select ..., (subconsulta) as alias'.
+func (o *seleccionar) CampoSubconsulta(subconsulta *seleccionar, alias string) *seleccionar {
+	o.camposSubconsultas = append(o.camposSubconsultas, fmt.Sprintf("(?) as %v", alias))
+	o.camposValores = append(o.camposValores, subconsulta)
+
+	return o
+}
+
+// Campos establece los nombres de campos a seleccionar.
+func (o *seleccionar) Campos(campos ...string) *seleccionar {
+	o.campos = campos
+	return o
+}
+
+// Condicion implementa la cláusula 'where' de la sentencia 'select'.
+// La condición puede contener parámetros nombrados (':nombre') cuyos valores
+// se reciben en un mapa, una estructura o con sql.Named(); '@nombre' es una
+// variable de sesión y no se reemplaza.
+// También puede recibir un predicado (Igual, Entre, Y, O, etc.).
+// Los valores que sean listas (slices) se expanden en tantos parámetros como
+// elementos contengan: Condicion("id in (?)", ids).
+// Los valores que sean sentencias 'select' se incorporan como subconsultas:
+// Condicion("id in (?)", bd.Seleccionar("-").Tabla("t").Campos("id")).
+func (o *seleccionar) Condicion(condicion interface{}, valores ...interface{}) *seleccionar {
+	texto, valores, err := resolverCondicion(condicion, valores, true)
+	if err != nil {
+		o.err = err
+		return o
+	}
+	if !o.senSQLExiste {
+		o.condicion = texto
+	}
+	o.condicionValores = valores
+
+	return o
+}
+
+// OrdenarPor implementa a la cláusula 'order by' de la sentencia 'select'.
+func (o *seleccionar) OrdenarPor(valores ...string) *seleccionar {
+	if !o.senSQLExiste {
+		o.ordenadoPor = valores
+	}
+
+	return o
+}
+
+// AgruparPor implementa a la cláusula 'group by' de la sentencia 'select'.
+func (o *seleccionar) AgruparPor(valores ...string) *seleccionar {
+	if !o.senSQLExiste {
+		o.agruparPor = valores
+	}
+	return o
+}
+
+// Teniendo implementa la cláusula 'having' de la sentencia 'select'.
+// Los valores que sean listas (slices), los parámetros nombrados y los
+// predicados se tratan al igual que en Condicion.
+func (o *seleccionar) Teniendo(condicion interface{}, valores ...interface{}) *seleccionar {
+	texto, valores, err := resolverCondicion(condicion, valores, true)
+	if err != nil {
+		o.err = err
+		return o
+	}
+	if !o.senSQLExiste {
+		o.teniendoCondicion = texto
+	}
+	o.teniendoValores = valores
+
+	return o
+}
+
+// Limitar implementa la cláusula 'limit' de la sentencia 'select'.
+func (o *seleccionar) Limitar(limite int) *seleccionar {
+	o.limite = limite
+
+	return o
+}
+
+// Saltar implementa la cláusula 'offset' de la sentencia 'select'.
+func (o *seleccionar) Saltar(salto int) *seleccionar {
+	o.salto = salto
+
+	return o
+}
+
+// Distinto implementa el modificador 'distinct' de la sentencia 'select':
+// descarta los registros repetidos.
+func (o *seleccionar) Distinto() *seleccionar {
+	if !o.senSQLExiste {
+		o.distinto = true
+	}
+
+	return o
+}
+
+// JuntaDirecta implementa el modificador 'straight_join' de la sentencia
+// 'select': las tablas se juntan en el orden en que se encuentran en la
+// sentencia.
+func (o *seleccionar) JuntaDirecta() *seleccionar {
+	if !o.senSQLExiste {
+		o.juntaDirecta = true
+	}
+
+	return o
+}
+
+// Sugerencia incorpora sugerencias al optimizador en la sentencia:
+// 'select /*+ MAX_EXECUTION_TIME(1000) */ ...'. Solo las admite el dialecto
+// Mysql.
+func (o *seleccionar) Sugerencia(sugerencias ...string) *seleccionar {
+	if !o.senSQLExiste {
+		o.sugerencias = append(o.sugerencias, sugerencias...)
+	}
+
+	return o
+}
+
+// UsarIndice implementa la sugerencia de índices 'use index (...)' para la
+// tabla principal o para una tabla juntada. La tabla se identifica por su
+// nombre o por su alias.
+func (o *seleccionar) UsarIndice(tabla string, indices ...string) *seleccionar {
+	return o.sugerirIndice(tabla, "use index", indices)
+}
+
+// ForzarIndice implementa la sugerencia de índices 'force index (...)' para
+// la tabla principal o para una tabla juntada. La tabla se identifica por su
+// nombre o por su alias.
+func (o *seleccionar) ForzarIndice(tabla string, indices ...string) *seleccionar {
+	return o.sugerirIndice(tabla, "force index", indices)
+}
+
+func (o *seleccionar) sugerirIndice(tabla, sugerencia string, indices []string) *seleccionar {
+	if o.senSQLExiste {
+		return o
+	}
+	if o.indices == nil {
+		o.indices = make(map[string]string)
+	}
+	o.indices[tabla] = fmt.Sprintf("%v (%v)", sugerencia, strings.Join(indices, ", "))
+
+	return o
+}
+
+// Ventana implementa la cláusula 'window' de la sentencia 'select': define
+// una ventana con nombre para las funciones de ventana de los campos
+// ('row_number() over nombre'). La partición y el orden pueden estar vacíos.
+//
+//	Ventana("w", "departamento_id", "salario desc")
+//	// window w as (partition by departamento_id order by salario desc)
+func (o *seleccionar) Ventana(nombre, particion, orden string) *seleccionar {
+	if o.senSQLExiste {
+		return o
+	}
+
+	var partes []string
+	if particion != "" {
+		partes = append(partes, "partition by "+particion)
+	}
+	if orden != "" {
+		partes = append(partes, "order by "+orden)
+	}
+	o.ventanas = append(o.ventanas, fmt.Sprintf("%v as (%v)", nombre, strings.Join(partes, " ")))
+
+	return o
+}
+
+// Con incorpora una expresión de tabla común (cláusula 'with') a la
+// sentencia: 'with nombre as (subconsulta) select ...'. El nombre puede
+// incluir la lista de columnas: Con("totales(persona_id, total)", sub).
+func (o *seleccionar) Con(nombre string, subconsulta *seleccionar) *seleccionar {
+	o.con = append(o.con, fmt.Sprintf("%v as (?)", nombre))
+	o.conValores = append(o.conValores, subconsulta)
+
+	return o
+}
+
+// ConRecursivo incorpora una expresión de tabla común recursiva a la
+// sentencia: 'with recursive nombre as (subconsulta) select ...'. La
+// subconsulta normalmente es la unión (UnirTodos) de la consulta inicial y de
+// la consulta recursiva.
+func (o *seleccionar) ConRecursivo(nombre string, subconsulta *seleccionar) *seleccionar {
+	o.conRecursivo = true
+
+	return o.Con(nombre, subconsulta)
+}
+
+// Unir implementa la cláusula 'union' de la sentencia 'select': incorpora
+// los registros de otra sentencia, descartando los registros repetidos.
+// El ordenamiento (OrdenarPor), el límite (Limitar) y el salto (Saltar) de
+// esta sentencia se aplican al resultado combinado.
+func (o *seleccionar) Unir(otra *seleccionar) *seleccionar {
+	o.uniones = append(o.uniones, "union")
+	o.unionesValores = append(o.unionesValores, otra)
+
+	return o
+}
+
+// UnirTodos implementa la cláusula 'union all' de la sentencia 'select':
+// incorpora todos los registros de otra sentencia, incluidos los repetidos.
+func (o *seleccionar) UnirTodos(otra *seleccionar) *seleccionar {
+	o.uniones = append(o.uniones, "union all")
+	o.unionesValores = append(o.unionesValores, otra)
+
+	return o
+}
+
+// JuntarCon implementa la cláusula 'inner join' de la sentencia 'select'.
+// La condición de la junta puede contener parámetros o ser un predicado.
+// Las juntas se incorporan a la sentencia en el orden en que se establecen.
+func (o *seleccionar) JuntarCon(tabla string, condicion interface{}, valores ...interface{}) *seleccionar {
+	return o.juntar("inner join", tabla, nil, condicion, valores)
+}
+
+// JuntarConSubconsulta implementa la cláusula 'inner join' de la sentencia
+// 'select' con una subconsulta como tabla derivada:
+// 'inner join (subconsulta) as alias on condicion'.
+func (o *seleccionar) JuntarConSubconsulta(subconsulta *seleccionar, alias string, condicion interface{}, valores ...interface{}) *seleccionar {
+	return o.juntar("inner join", fmt.Sprintf("(?) as %v", alias), subconsulta, condicion, valores)
+}
+
+// JuntarIzquierda implementa la cláusula 'left join' de la sentencia 'select'.
+func (o *seleccionar) JuntarIzquierda(tabla string, condicion interface{}, valores ...interface{}) *seleccionar {
+	return o.juntar("left join", tabla, nil, condicion, valores)
+}
+
+// JuntarIzquierdaSubconsulta implementa la cláusula 'left join' de la
+// sentencia 'select' con una subconsulta como tabla derivada:
+// 'left join (subconsulta) as alias on condicion'.
+func (o *seleccionar) JuntarIzquierdaSubconsulta(subconsulta *seleccionar, alias string, condicion interface{}, valores ...interface{}) *seleccionar {
+	return o.juntar("left join", fmt.Sprintf("(?) as %v", alias), subconsulta, condicion, valores)
+}
+
+// JuntarDerecha implementa la cláusula 'right join' de la sentencia 'select'.
+func (o *seleccionar) JuntarDerecha(tabla string, condicion interface{}, valores ...interface{}) *seleccionar {
+	return o.juntar("right join", tabla, nil, condicion, valores)
+}
+
+// JuntarExterior implementa la junta externa completa ('full outer join') de
+// la sentencia 'select'. Dado que Mysql y Mariadb no la admiten, se emula con
+// la unión de la sentencia con 'left join' y la sentencia con 'right join':
+// 'select ... left join t on c ... union select ... right join t on c ...'.
+// Se admite una sola junta externa completa por sentencia.
+func (o *seleccionar) JuntarExterior(tabla string, condicion interface{}, valores ...interface{}) *seleccionar {
+	return o.juntar(juntaExterior, tabla, nil, condicion, valores)
+}
+
+// JuntarCruzado implementa la cláusula 'cross join' de la sentencia 'select'.
+func (o *seleccionar) JuntarCruzado(tabla string) *seleccionar {
+	o.juntas = append(o.juntas, junta{tipo: "cross join", tabla: tabla})
+	return o
+}
+
+// JuntarNatural implementa la cláusula 'natural join' de la sentencia
+// 'select': junta las tablas por los campos con el mismo nombre.
+func (o *seleccionar) JuntarNatural(tabla string) *seleccionar {
+	o.juntas = append(o.juntas, junta{tipo: "natural join", tabla: tabla})
+	return o
+}
+
+// JuntarUsando implementa la cláusula 'inner join ... using (...)' de la
+// sentencia 'select': junta las tablas por los campos recibidos, que deben
+// tener el mismo nombre en ambas tablas.
+func (o *seleccionar) JuntarUsando(tabla string, campos ...string) *seleccionar {
+	if len(campos) == 0 {
+		o.err = errorNuevo().asignarMotivoNombresDeCamposVacios()
+		return o
+	}
+	o.juntas = append(o.juntas, junta{tipo: "inner join", tabla: tabla, usando: campos})
+	return o
+}
+
+func (o *seleccionar) juntar(tipo, tabla string, subconsulta *seleccionar, condicion interface{}, valores []interface{}) *seleccionar {
+	texto, valores, err := resolverCondicion(condicion, valores, false)
+	if err != nil {
+		o.err = err
+		return o
+	}
+	if subconsulta != nil {
+		valores = append([]interface{}{subconsulta}, valores...)
+	}
+	o.juntas = append(o.juntas, junta{tipo: tipo, tabla: tabla, condicion: texto, valores: valores})
+
+	return o
+}
+
+// IncluirEliminados establece que la consulta incluya los registros con
+// borrado lógico de las tablas registradas con BD.BorradoLogico().
+func (o *seleccionar) IncluirEliminados() *seleccionar {
+	o.eliminados = eliminadosIncluir
+	return o
+}
+
+// SoloEliminados establece que la consulta obtenga únicamente los registros
+// con borrado lógico de la tabla principal (registrada con BD.BorradoLogico()).
+func (o *seleccionar) SoloEliminados() *seleccionar {
+	o.eliminados = eliminadosSolo
+	return o
+}
+
+// ParaModificar implementa la cláusula 'for update' de la sentencia 'select':
+// bloquea los registros obtenidos hasta que finalice la transacción.
+// Solo puede utilizarse dentro de una transacción.
+func (o *seleccionar) ParaModificar() *seleccionar {
+	o.bloqueo = bloqueoModificar
+	return o
+}
+
+// ParaCompartir implementa la cláusula 'for share' ('lock in share mode' en
+// MariaDB) de la sentencia 'select': bloquea los registros obtenidos para que
+// no sean modificados hasta que finalice la transacción.
+// Solo puede utilizarse dentro de una transacción.
+func (o *seleccionar) ParaCompartir() *seleccionar {
+	o.bloqueo = bloqueoCompartir
+	return o
+}
+
+// SinEspera implementa la opción 'nowait' del bloqueo de registros: si algún
+// registro se encuentra bloqueado por otra transacción, la sentencia devuelve
+// un error de bloqueo no disponible en lugar de esperar.
+func (o *seleccionar) SinEspera() *seleccionar {
+	o.bloqueoEspera = bloqueoSinEspera
+	return o
+}
+
+// SaltarBloqueados implementa la opción 'skip locked' del bloqueo de
+// registros: los registros bloqueados por otra transacción no se obtienen.
+func (o *seleccionar) SaltarBloqueados() *seleccionar {
+	o.bloqueoEspera = bloqueoSaltar
+	return o
+}
+
+// Resultado recibe el objeto donde se almacena el resultado de la consulta.
+// Debe ser un puntero de slice de una estructura.
+//	Ejemplo:
+//	var elementos = []struct {
+//		ID            int64  `bdsql:"id"`
+//		Nombre        string `bdsql:"nombre"`
+//		EsActivo      bool   `bdsql:"es_activo"`
+//		Observaciones string `bdsql:"observaciones"`
+//	}{}
+//	var cant, err = bd.Seleccionar("consulta").
+//					Tabla("elementos").
+//					Campos("*").
+//					Resultado(&elementos).
+//					Ejecutar()
+//	if err != nil {
+//		fmt.Println(err)
+//		return err
+//	}
+//	fmt.Println(cant, elementos)
+//
+func (o *seleccionar) Resultado(objeto interface{}) *seleccionar {
+	o.objeto = objeto
+	return o
+}
+
+// SQL devuelve la sentencia SQL.
+func (o *seleccionar) SQL() (string, error) {
+	var sentencia, err = o.generarSQL()
+	if err != nil {
+		return "", err
+	}
+	sentencia, _, err = expandirSentencia(sentencia, o.parametros())
+
+	return sentencia, err
+}
+
+// SQLInterpolado devuelve la sentencia SQL con los valores incorporados como
+// literales, para registrarla o copiarla en una consola. Solo debe utilizarse
+// con ese fin: las sentencias se ejecutan siempre con parámetros.
+func (o *seleccionar) SQLInterpolado() (string, error) {
+	var sentencia, err = o.generarSQL()
+	if err != nil {
+		return "", err
+	}
+	sentencia, valores, err := expandirSentencia(sentencia, o.parametros())
+	if err != nil {
+		return "", err
+	}
+
+	return o.bd.interpolarSentencia(sentencia, valores)
+}
+
+// Ejecutar ejecuta la sentencia SQL.
+func (o *seleccionar) Ejecutar() (int, error) {
+	var sentencia, err = o.generarSQL()
+	if err != nil {
+		return 0, err
+	}
+
+	// validar que el objeto de resultado, sea un puntero de slice de estructura
+	// o un puntero de mapa (ResultadoMapa)
+	var esMapa = reflect.TypeOf(o.objeto).Kind() == reflect.Ptr && reflect.TypeOf(o.objeto).Elem().Kind() == reflect.Map
+	if ok := reflect.TypeOf(o.objeto).Kind() == reflect.Ptr && reflect.TypeOf(o.objeto).Elem().Kind() == reflect.Slice && reflect.TypeOf(o.objeto).Elem().Elem().Kind() == reflect.Struct; !ok && !esMapa {
+		return 0, errorNuevo().asignarMotivoSeleccionarPunteroDeSlice()
+	}
+
+	// slice de parámetros de la sentencia sql a ejecutar
+	sentencia, parametros, err := expandirSentencia(sentencia, o.parametros())
+	if err != nil {
+		return 0, err
+	}
+
+	filas, err := o.conexion.QueryContext(context.Background(), sentencia, parametros...)
+	if err != nil {
+		return 0, resolverErrorBloqueo(err, o.bd.obtenerDialecto(), o.bloqueo != bloqueoNinguno && o.bloqueoEspera != bloqueoEsperar)
+	}
+	defer filas.Close()
+
+	var cant int
+	if esMapa {
+		cant, err = asignarAMapa(filas, o.objeto)
+	} else {
+		cant, err = asignarAObjeto(filas, o.objeto)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return cant, nil
+}
+
+// parametros devuelve los valores de los parámetros de la sentencia en el
+// orden en que se encuentran en ella.
+func (o *seleccionar) parametros() []interface{} {
+	var parametros []interface{}
+	// with
+	parametros = append(parametros, o.conValores...)
+	// subconsultas de los campos, de la tabla derivada, joins, where y
+	// having (dos veces si se emula la junta externa completa)
+	parametros = append(parametros, o.parametrosNucleo()...)
+	if o.tieneJuntaExterior() {
+		parametros = append(parametros, o.parametrosNucleo()...)
+	}
+	// union
+	parametros = append(parametros, o.unionesValores...)
+
+	return parametros
+}
+
+// parametrosNucleo devuelve los valores de los parámetros de la sentencia
+// generada por generarNucleo.
+func (o *seleccionar) parametrosNucleo() []interface{} {
+	var parametros []interface{}
+	// subconsultas de los campos y de la tabla derivada
+	parametros = append(parametros, o.camposValores...)
+	parametros = append(parametros, o.tablaValores...)
+	// joins
+	for _, j := range o.juntas {
+		parametros = append(parametros, j.valores...)
+	}
+	// where
+	if o.condicion != "" {
+		parametros = append(parametros, o.condicionValores...)
+	}
+	parametros = append(parametros, o.cursorValores...)
+	// having
+	if o.teniendoCondicion != "" {
+		parametros = append(parametros, o.teniendoValores...)
+	}
+
+	return parametros
+}
+
+func (o *seleccionar) generarSQL() (string, error) {
+	if o.err != nil {
+		return "", o.err
+	}
+	if o.senSQLExiste {
+		return o.senSQL, nil
+	}
+
+	var err = errorNuevo()
+	// verificar que el nombre de la tabla no se encuentre vacía
+	if o.tabla == "" {
+		err.asignarMotivoNombreDeTablaVacia()
+	}
+	// verificar que los nombres de campos no se encuentren vacíos
+	if len(o.campos) == 0 && len(o.camposSubconsultas) == 0 {
+		err.asignarMotivoNombresDeCamposVacios()
+	}
+	// verificar que el bloqueo de registros se realice dentro de una transacción
+	if o.bloqueo != bloqueoNinguno && !enTransaccion(o.conexion) {
+		err.asignarMotivoBloqueoFueraDeTransaccion()
+	}
+	// verificar que el dialecto admita las sugerencias al optimizador
+	if len(o.sugerencias) > 0 && !o.bd.obtenerDialecto().admiteSugerencias() {
+		err.asignarMotivoNoAdmitidoPorDialecto("sugerencias al optimizador")
+	}
+	// verificar que exista una sola junta externa completa (emulada)
+	var exteriores int
+	for _, j := range o.juntas {
+		if j.tipo == juntaExterior {
+			exteriores++
+		}
+	}
+	if exteriores > 1 {
+		err.asignarMotivoNoAdmitidoPorDialecto("más de una junta externa completa ('full outer join')")
+	}
+	if len(err.mensajes) != 0 {
+		return "", err
+	}
+	// condición del cursor de paginación
+	o.cursorCondicion, o.cursorValores = "", nil
+	if o.cursor != "" {
+		if len(o.ordenadoPor) == 0 || len(o.uniones) > 0 || exteriores > 0 {
+			return "", errorNuevo().asignarMotivoCursorSinOrdenamiento()
+		}
+		valores, err := o.bd.decodificarCursor(o.cursor, len(o.ordenadoPor))
+		if err != nil {
+			return "", err
+		}
+		o.cursorCondicion, o.cursorValores = condicionCursor(o.ordenadoPor, valores)
+	}
+	// sentencia: la junta externa completa se emula con la unión de la
+	// sentencia con 'left join' y la sentencia con 'right join'
+	var sentencia = o.generarNucleo("left join")
+	if exteriores > 0 {
+		sentencia += " union " + o.generarNucleo("right join")
+	}
+	// with
+	if len(o.con) > 0 {
+		var with = "with"
+		if o.conRecursivo {
+			with = "with recursive"
+		}
+		sentencia = fmt.Sprintf("%v %v %v", with, strings.Join(o.con, ", "), sentencia)
+	}
+	// union: las sentencias unidas con su propio ordenamiento o límite se
+	// encierran entre paréntesis
+	for i, union := range o.uniones {
+		if otra := o.unionesValores[i].(*seleccionar); len(otra.ordenadoPor) > 0 || otra.limite > 0 || otra.salto > 0 {
+			sentencia += fmt.Sprintf(" %v (?)", union)
+		} else {
+			sentencia += fmt.Sprintf(" %v ?", union)
+		}
+	}
+	// order by
+	if len(o.ordenadoPor) > 0 {
+		sentencia += fmt.Sprintf(" order by %v", strings.Join(o.ordenadoPor, ", "))
+	}
+	// limit
+	if o.limite > 0 {
+		sentencia += fmt.Sprintf(" limit %v", o.limite)
+	}
+	// offset: el motor no admite 'offset' sin 'limit'; se utiliza el mayor
+	// valor admitido (sin límite)
+	if o.salto > 0 {
+		if o.limite == 0 {
+			sentencia += " limit 18446744073709551615"
+		}
+		sentencia += fmt.Sprintf(" offset %v", o.salto)
+	}
+	// for update / for share
+	switch o.bloqueo {
+	case bloqueoModificar:
+		sentencia += " for update"
+	case bloqueoCompartir:
+		sentencia += " " + o.bd.obtenerDialecto().clausulaCompartir()
+	}
+	if o.bloqueo != bloqueoNinguno {
+		switch o.bloqueoEspera {
+		case bloqueoSinEspera:
+			sentencia += " nowait"
+		case bloqueoSaltar:
+			sentencia += " skip locked"
+		}
+	}
+
+	return fmt.Sprintf("%v;", sentencia), nil
+}
+
+// generarNucleo genera la sentencia 'select' desde sus campos hasta la
+// cláusula 'window'. La junta externa completa se genera con el tipo de junta
+// recibido ('left join' o 'right join').
+func (o *seleccionar) generarNucleo(exterior string) string {
+	var modificadores string
+	if len(o.sugerencias) > 0 {
+		modificadores += fmt.Sprintf("/*+ %v */ ", strings.Join(o.sugerencias, " "))
+	}
+	if o.distinto {
+		modificadores += "distinct "
+	}
+	if o.juntaDirecta {
+		modificadores += "straight_join "
+	}
+	var campos = append(append([]string{}, o.campos...), o.camposSubconsultas...)
+	var sentencia = fmt.Sprintf("select %v%v from %v", modificadores, strings.Join(campos, ", "), o.tablaConIndice(o.tabla))
+	// joins: el filtro de borrado lógico de la tabla juntada se incorpora a
+	// la cláusula 'on' de las juntas internas y por izquierda, y a la
+	// cláusula 'where' en las demás juntas ('using', natural, cruzada y por
+	// derecha, en la que la tabla juntada conserva todos sus registros)
+	var filtros []string
+	for _, j := range o.juntas {
+		var tipo = j.tipo
+		if tipo == juntaExterior {
+			tipo = exterior
+		}
+		switch {
+		case j.usando != nil:
+			sentencia += fmt.Sprintf(" %v %v using (%v)", tipo, o.tablaConIndice(j.tabla), strings.Join(j.usando, ", "))
+			filtros = o.agregarFiltroJunta(filtros, j.tabla)
+		case j.condicion == "":
+			sentencia += fmt.Sprintf(" %v %v", tipo, o.tablaConIndice(j.tabla))
+			filtros = o.agregarFiltroJunta(filtros, j.tabla)
+		case tipo == "inner join" || tipo == "left join":
+			sentencia += fmt.Sprintf(" %v %v on %v", tipo, o.tablaConIndice(j.tabla), o.condicionJunta(j.tabla, j.condicion))
+		default:
+			sentencia += fmt.Sprintf(" %v %v on %v", tipo, o.tablaConIndice(j.tabla), j.condicion)
+			filtros = o.agregarFiltroJunta(filtros, j.tabla)
+		}
+	}
+	// where
+	var condicion = o.condicionConEliminados(filtros)
+	if o.cursorCondicion != "" {
+		if condicion == "" {
+			condicion = o.cursorCondicion
+		} else {
+			condicion = fmt.Sprintf("(%v) and %v", condicion, o.cursorCondicion)
+		}
+	}
+	if condicion != "" {
+		sentencia += fmt.Sprintf(" where %v", condicion)
+	}
+	// group by
+	if len(o.agruparPor) > 0 {
+		sentencia += fmt.Sprintf(" group by %v", strings.Join(o.agruparPor, ", "))
+	}
+	// having
+	if o.teniendoCondicion != "" {
+		sentencia += fmt.Sprintf(" having %v", o.teniendoCondicion)
+	}
+	// window
+	if len(o.ventanas) > 0 {
+		sentencia += fmt.Sprintf(" window %v", strings.Join(o.ventanas, ", "))
+	}
+
+	return sentencia
+}
+
+// junta representa una junta de la sentencia 'select'.
+type junta struct {
+	tipo      string        // 'inner join', 'left join', 'right join', 'cross join', etc.
+	tabla     string        // tabla juntada (puede incluir su alias)
+	condicion string        // condición de la cláusula 'on'
+	usando    []string      // campos de la cláusula 'using'
+	valores   []interface{} // valores de los parámetros de la tabla y de la condición
+}
+
+// juntaExterior identifica a la junta externa completa, emulada con la unión
+// de 'left join' y 'right join'.
+const juntaExterior = "full outer join"
+
+// tieneJuntaExterior informa si la sentencia contiene una junta externa
+// completa.
+func (o *seleccionar) tieneJuntaExterior() bool {
+	for _, j := range o.juntas {
+		if j.tipo == juntaExterior {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bloqueo de los registros obtenidos
+const (
+	bloqueoNinguno   = iota // sin bloqueo (predeterminado)
+	bloqueoModificar        // 'for update'
+	bloqueoCompartir        // 'for share' / 'lock in share mode'
+)
+
+// espera de los registros bloqueados por otra transacción
+const (
+	bloqueoEsperar   = iota // esperar a que se liberen los registros (predeterminado)
+	bloqueoSinEspera        // 'nowait'
+	bloqueoSaltar           // 'skip locked'
+)
+
+// generarSQLSubconsulta devuelve la sentencia (sin el ';' final) y sus
+// valores, para ser incorporada dentro de otra sentencia.
+func (o *seleccionar) generarSQLSubconsulta() (string, []interface{}, error) {
+	var sentencia, err = o.generarSQL()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return expandirSentencia(strings.TrimSuffix(sentencia, ";"), o.parametros())
+}
+
+// Contar ejecuta una sentencia que obtiene la cantidad de registros de la
+// sentencia, utilizando las mismas tablas, juntas, condiciones y
+// agrupamiento. Se ignoran el ordenamiento, el límite y el salto. Las
+// sentencias agrupadas se cuentan como subconsulta.
+func (o *seleccionar) Contar() (int64, error) {
+	sentencia, valores, err := o.generarSQLContar()
+	if err != nil {
+		return 0, err
+	}
+
+	var cant int64
+	if err := o.consultarValor(sentencia, valores, &cant); err != nil {
+		return 0, err
+	}
+
+	return cant, nil
+}
+
+// Existe ejecuta una sentencia que informa si la sentencia obtiene al menos
+// un registro, utilizando las mismas tablas, juntas, condiciones y
+// agrupamiento. Se ignoran el ordenamiento, el límite y el salto.
+func (o *seleccionar) Existe() (bool, error) {
+	sentencia, valores, err := o.generarSQLExiste()
+	if err != nil {
+		return false, err
+	}
+
+	var existe bool
+	if err := o.consultarValor(sentencia, valores, &existe); err != nil {
+		return false, err
+	}
+
+	return existe, nil
+}
+
+// sinOrden devuelve una copia de la sentencia sin ordenamiento, límite,
+// salto, cursor ni bloqueo, para obtener la cantidad o la existencia de sus
+// registros.
+func (o *seleccionar) sinOrden() seleccionar {
+	var c = *o
+	c.ordenadoPor, c.limite, c.salto, c.cursor = nil, 0, 0, ""
+	c.bloqueo = bloqueoNinguno
+	c.senSQLExiste = false
+
+	return c
+}
+
+// esAgregada informa si los registros de la sentencia resultan de un
+// agrupamiento, 'distinct', uniones o ventanas: no es posible reemplazar sus
+// campos para contarlos.
+func (o *seleccionar) esAgregada() bool {
+	return len(o.agruparPor) > 0 || o.teniendoCondicion != "" || o.distinto || len(o.uniones) > 0 || len(o.ventanas) > 0 || o.tieneJuntaExterior()
+}
+
+// generarSQLContar devuelve la sentencia que obtiene la cantidad de registros
+// de la sentencia y sus valores.
+func (o *seleccionar) generarSQLContar() (string, []interface{}, error) {
+	var c = o.sinOrden()
+	if c.esAgregada() {
+		subconsulta, valores, err := c.generarSQLSubconsulta()
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("select count(*) from (%v) as t;", subconsulta), valores, nil
+	}
+
+	c.campos, c.camposSubconsultas, c.camposValores = []string{"count(*)"}, nil, nil
+	sentencia, err := c.generarSQL()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return expandirSentencia(sentencia, c.parametros())
+}
+
+// generarSQLExiste devuelve la sentencia que informa si la sentencia obtiene
+// al menos un registro y sus valores.
+func (o *seleccionar) generarSQLExiste() (string, []interface{}, error) {
+	var c = o.sinOrden()
+	if !c.esAgregada() {
+		c.campos, c.camposSubconsultas, c.camposValores = []string{"1"}, nil, nil
+	}
+	subconsulta, valores, err := c.generarSQLSubconsulta()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return fmt.Sprintf("select exists (%v);", subconsulta), valores, nil
+}
+
+// consultarValor ejecuta una sentencia que obtiene un único valor.
+func (o *seleccionar) consultarValor(sentencia string, valores []interface{}, destino interface{}) error {
+	var fila = o.conexion.QueryRowContext(context.Background(), sentencia, valores...)
+	if err := fila.Scan(destino); err != nil {
+		return resolverErrorMysql(err)
+	}
+
+	return nil
+}
+
+// tablaConIndice devuelve la tabla con su sugerencia de índices, si se
+// estableció alguna para la tabla, su nombre o su alias.
+func (o *seleccionar) tablaConIndice(tabla string) string {
+	if len(o.indices) == 0 {
+		return tabla
+	}
+	nombre, alias := tablaYAlias(tabla)
+	for _, clave := range []string{tabla, alias, nombre} {
+		if indice, ok := o.indices[clave]; ok {
+			return fmt.Sprintf("%v %v", tabla, indice)
+		}
+	}
+
+	return tabla
+}
+
+// tratamiento de los registros con borrado lógico
+const (
+	eliminadosExcluir = iota // excluir los registros eliminados (predeterminado)
+	eliminadosIncluir        // incluir los registros eliminados
+	eliminadosSolo           // obtener solo los registros eliminados
+)
+
+// condicionConEliminados devuelve la condición de la cláusula 'where'
+// incorporando el filtro de borrado lógico de la tabla principal y los
+// filtros recibidos de las tablas juntadas.
+func (o *seleccionar) condicionConEliminados(filtrosJuntas []string) string {
+	var filtros []string
+	if o.eliminados != eliminadosIncluir {
+		nombre, alias := tablaYAlias(o.tabla)
+		if campo, ok := o.bd.campoBorradoLogico(nombre); ok {
+			if o.eliminados == eliminadosSolo {
+				filtros = append(filtros, fmt.Sprintf("%v.%v is not null", alias, campo))
+			} else {
+				filtros = append(filtros, fmt.Sprintf("%v.%v is null", alias, campo))
+			}
+		}
+	}
+	filtros = append(filtros, filtrosJuntas...)
+	if len(filtros) == 0 {
+		return o.condicion
+	}
+	if o.condicion == "" {
+		return strings.Join(filtros, " and ")
+	}
+
+	return fmt.Sprintf("(%v) and %v", o.condicion, strings.Join(filtros, " and "))
+}
+
+// filtroJunta devuelve el filtro de borrado lógico de la tabla juntada: se
+// excluyen sus registros eliminados, salvo que se incluyan todos los
+// registros (IncluirEliminados).
+func (o *seleccionar) filtroJunta(tabla string) (string, bool) {
+	if o.eliminados == eliminadosIncluir {
+		return "", false
+	}
+	nombre, alias := tablaYAlias(tabla)
+	campo, ok := o.bd.campoBorradoLogico(nombre)
+	if !ok {
+		return "", false
+	}
+
+	return fmt.Sprintf("%v.%v is null", alias, campo), true
+}
+
+// agregarFiltroJunta agrega a los filtros de la cláusula 'where' el filtro de
+// borrado lógico de la tabla juntada, si lo tiene.
+func (o *seleccionar) agregarFiltroJunta(filtros []string, tabla string) []string {
+	if filtro, ok := o.filtroJunta(tabla); ok {
+		return append(filtros, filtro)
+	}
+
+	return filtros
+}
+
+// condicionJunta devuelve la condición de la junta incorporando el filtro de
+// borrado lógico de la tabla juntada. Se aplica a las juntas internas y por
+// izquierda.
+func (o *seleccionar) condicionJunta(tabla, condicion string) string {
+	if filtro, ok := o.filtroJunta(tabla); ok {
+		return fmt.Sprintf("(%v) and %v", condicion, filtro)
+	}
+
+	return condicion
+}
+
+func asignarAObjeto(filas *sql.Rows, objeto interface{}) (int, error) {
+	// nombres de campos del resultado obtenido de la base de datos.
+	camposFila, err := filas.Columns()
+	if err != nil {
+		return 0, resolverErrorMysql(err)
+	}
+
+	// obtener la lista de campos de la estructura que podrían ser asignados
+	// desde la consulta obtenida.
+	estructura := reflect.TypeOf(objeto).Elem().Elem()
+
+	// mapa de estructura del objeto:
+	// la clave es el valor de la etiqueta.
+	// el valor es el nombre del campo de la estructura.
+	camposEstructura := relacionDeCampos(estructura)
+
+	// podría pasar (raramente) que todos los campos de la estructura contengan
+	// el tag `bdsql:"-"`. En ese caso, la estrucutra no permite que ninguno de
+	// sus campos sean asignables por los valores recibidos de la consulta SQL.
+	if len(camposEstructura) == 0 {
+		return 0, errorNuevo().asignarMotivoSeleccionarCamposSinRelacion()
+	}
+
+	// mapa de funciones:
+	// se crea un slice el cuál mantiene la función que completa cada campo
+	// de la estructura.
+	funciones := make(map[string]func(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error), estructura.NumField())
+
+	// verificar qe todos los campos de la consulta obtenida, puedan ser
+	// ingresados en el objeto recibido.
+	var camposFaltantes []string
+	estructuraNueva := reflect.New(estructura).Elem()
+	for _, campoFila := range camposFila {
+		campoEstructura, ok := camposEstructura[campoFila]
+		if !ok {
+			camposFaltantes = append(camposFaltantes, campoFila)
+			continue
+		}
+
+		// llenar el slice de funciones según cada tipo de campo de la estructura.
+		funcion, err := funcionDeAsignacion(estructuraNueva.FieldByName(campoEstructura).Type())
+		if err != nil {
+			return 0, err
+		}
+		funciones[campoEstructura] = funcion
+	}
+	if len(camposFaltantes) > 0 {
+		return 0, errorNuevo().asignarMotivoSeleccionarCamposFaltantes(strings.Join(camposFaltantes, ","))
+	}
+
+	// obtener los valores de los campos de la base de datos.
+	var valores = make([]interface{}, len(camposFila))
+	for i := range valores {
+		var ii interface{}
+		valores[i] = &ii
+	}
+
+	// sabiendo que el objeto es un puntero de slice de una estructura se
+	// asigna un elemento para ir incorporando las estructuras.
+	sliceDeObjetos := reflect.ValueOf(objeto).Elem()
+
+	// recorrer todas las filas e insertarlas en el objeto.
+	var cant int
+	for filas.Next() {
+		cant++
+
+		if err := filas.Scan(valores...); err != nil {
+			return 0, errorNuevo().asignarOrigen(err).asignarMotivoSeleccionarLecturaDeCampos()
+		}
+
+		for i, campoFila := range camposFila {
+			var valorCrudo = *(valores[i].(*interface{}))
+			var tipoCrudo = reflect.TypeOf(valorCrudo)
+
+			campoEstructura, ok := camposEstructura[campoFila]
+			if !ok {
+				return 0, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos(fmt.Sprintf("Inexistente: %v, Campo: %v", err, campoFila))
+			}
+
+			valor, err := funciones[campoEstructura](valorCrudo, tipoCrudo)
+			if err != nil {
+				return 0, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos(fmt.Sprintf("Error: %v, Campo: %v", err, campoFila))
+			}
+			estructuraNueva.FieldByName(campoEstructura).Set(valor)
+		}
+
+		sliceDeObjetos.Set(reflect.Append(sliceDeObjetos, estructuraNueva))
+	}
+
+	return cant, nil
+}
+
+// relacionDeCampos devuelve la relación entre los campos de la tabla y los
+// campos de la estructura: la clave es el nombre del campo de la tabla (valor
+// de la etiqueta "bdsql", sin sus opciones) y el valor es el nombre del campo
+// de la estructura.
+func relacionDeCampos(estructura reflect.Type) map[string]string {
+	camposEstructura := make(map[string]string)
+	for i := 0; i < estructura.NumField(); i++ {
+		campo := estructura.Field(i)
+		campoTabla, _ := etiquetaBdsql(campo)
+
+		switch campoTabla {
+		case "-":
+			// no asignar el valor
+			continue
+		case "":
+			// en caso que el campo de la estructura no tenga asignado el
+			// tag "bdsql", se asume que el nombre del campo de la la consulta
+			// SQL, es el mismo (en minúsculas) que el nombre de campo de la
+			// estructura.
+			camposEstructura[strings.ToLower(campo.Name)] = campo.Name
+		default:
+			// en caso que el campo de la estructura tenga asignado el
+			// tag "bdsql", se toma el tag para obtener el dato de la
+			// consulta SQL.
+			camposEstructura[campoTabla] = campo.Name
+		}
+	}
+
+	return camposEstructura
+}
+
+// etiquetaBdsql devuelve el nombre del campo de la tabla y las opciones de la
+// etiqueta "bdsql" del campo de la estructura: `bdsql:"id,clave"` devuelve
+// "id" y [clave].
+func etiquetaBdsql(campo reflect.StructField) (string, []string) {
+	var partes = strings.Split(campo.Tag.Get("bdsql"), ",")
+	for i := range partes {
+		partes[i] = strings.Trim(partes[i], " ")
+	}
+
+	return partes[0], partes[1:]
+}
+
+// ---- Funciones de asignación de campos de la estructura ---------------------
+
+// tipoScanner es el tipo de la interfaz sql.Scanner.
+var tipoScanner = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// funcionDeAsignacion devuelve la función que convierte el valor obtenido de
+// la base de datos al tipo recibido. Los tipos que implementan sql.Scanner
+// (sql.NullString, sql.NullInt64, sql.NullTime, etc.) asignan su propio
+// valor, lo que permite obtener columnas con valores nulos.
+func funcionDeAsignacion(tipo reflect.Type) (func(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error), error) {
+	if reflect.PtrTo(tipo).Implements(tipoScanner) {
+		return valorScanner(tipo), nil
+	}
+
+	switch tipo.Kind() {
+	case reflect.Int:
+		return valori, nil
+	case reflect.Int8:
+		return valori8, nil
+	case reflect.Int16:
+		return valori16, nil
+	case reflect.Int32:
+		return valori32, nil
+	case reflect.Int64:
+		return valori64, nil
+	case reflect.Uint:
+		return valorui, nil
+	case reflect.Uint8:
+		return valorui8, nil
+	case reflect.Uint16:
+		return valorui16, nil
+	case reflect.Uint32:
+		return valorui32, nil
+	case reflect.Uint64:
+		return valorui64, nil
+	case reflect.Float32:
+		return valorf32, nil
+	case reflect.Float64:
+		return valorf64, nil
+	case reflect.Bool:
+		return valorbool, nil
+	case reflect.String:
+		return valorstring, nil
+	case reflect.Struct:
+		if tipo.String() == "time.Time" {
+			return valorFecha, nil
+		}
+		return nil, errorNuevo().asignarMotivoSeleccionarContieneEstructura()
+	}
+
+	return nil, errorNuevo().asignarMotivoSeleccionarTipoDeCampoIncorrecto()
+}
+
+// valorScanner devuelve la función que asigna el valor obtenido de la base
+// de datos a un tipo que implementa sql.Scanner.
+func valorScanner(tipo reflect.Type) func(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
+	return func(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
+		var v = reflect.New(tipo)
+		if err := v.Interface().(sql.Scanner).Scan(valorCrudo); err != nil {
+			return reflect.Value{}, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos(err.Error())
+		}
+
+		return v.Elem(), nil
+	}
+}
+
+func valori(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
+	var vacio reflect.Value
+	var v int
+
+	if tipoCrudo != nil {
+		if tipoCrudo.String() == "[]uint8" {
+			s, ok := valorCrudo.([]uint8)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
+			}
+			vTemp, err := strconv.Atoi(string(s))
+			if err != nil {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo int")
+			}
+			v = vTemp
+		} else {
+			vTemp, ok := valorCrudo.(int64)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo int64")
+			}
+			v = int(vTemp)
+		}
+	}
+
+	return reflect.ValueOf(v), nil
+}
+
+func valori8(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
+	var vacio reflect.Value
+	var v int8
+
+	if tipoCrudo != nil {
+		if tipoCrudo.String() == "[]uint8" {
+			s, ok := valorCrudo.([]uint8)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
+			}
+			vTemp, err := strconv.ParseInt(string(s), 10, 8)
+			if err != nil {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo int8")
+			}
+			v = int8(vTemp)
+		} else {
+			vTemp, ok := valorCrudo.(int64)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo int64")
+			}
+			v = int8(vTemp)
+		}
+	}
+
+	return reflect.ValueOf(v), nil
+}
+
+func valori16(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
+	var vacio reflect.Value
+	var v int16
+
+	if tipoCrudo != nil {
+		if tipoCrudo.String() == "[]uint8" {
+			s, ok := valorCrudo.([]uint8)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
+			}
+			vTemp, err := strconv.ParseInt(string(s), 10, 16)
+			if err != nil {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo int16")
+			}
+			v = int16(vTemp)
+		} else {
+			vTemp, ok := valorCrudo.(int64)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo int64")
+			}
+			v = int16(vTemp)
+		}
+	}
+
+	return reflect.ValueOf(v), nil
+}
+
+func valori32(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
+	var vacio reflect.Value
+	var v int32
+
+	if tipoCrudo != nil {
+		if tipoCrudo.String() == "[]uint8" {
+			s, ok := valorCrudo.([]uint8)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
+			}
+			vTemp, err := strconv.ParseInt(string(s), 10, 32)
+			if err != nil {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo int32")
+			}
+			v = int32(vTemp)
+		} else {
+			vTemp, ok := valorCrudo.(int64)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo int64")
+			}
+			v = int32(vTemp)
+		}
+	}
+
+	return reflect.ValueOf(v), nil
+}
+
+func valori64(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
+	var vacio reflect.Value
+	var v int64
+
+	if tipoCrudo != nil {
+		if tipoCrudo.String() == "[]uint8" {
+			s, ok := valorCrudo.([]uint8)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
+			}
+			vTemp, err := strconv.ParseInt(string(s), 10, 64)
+			if err != nil {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo int64")
+			}
+			v = vTemp
+		} else {
+			vTemp, ok := valorCrudo.(int64)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo int64")
+			}
+			v = vTemp
+		}
+	}
+
+	return reflect.ValueOf(v), nil
+}
+
+func valorui(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
+	var vacio reflect.Value
+	var v uint
+
+	if tipoCrudo != nil {
+		if tipoCrudo.String() == "[]uint8" {
+			s, ok := valorCrudo.([]uint8)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
+			}
+			vTemp, err := strconv.ParseUint(string(s), 10, 64)
+			if err != nil {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo uint")
+			}
+			v = uint(vTemp)
+		} else {
+			vTemp, ok := valorCrudo.(uint)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo uint")
+			}
+			v = uint(vTemp)
+		}
+	}
+
+	return reflect.ValueOf(v), nil
+}
+
+func valorui8(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
+	var vacio reflect.Value
+	var v uint8
+
+	if tipoCrudo != nil {
+		if tipoCrudo.String() == "[]uint8" {
+			s, ok := valorCrudo.([]uint8)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
+			}
+			vTemp, err := strconv.ParseUint(string(s), 10, 8)
+			if err != nil {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo uint8")
+			}
+			v = uint8(vTemp)
+		} else {
+			vTemp, ok := valorCrudo.(uint8)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo uint8")
+			}
+			v = vTemp
+		}
+	}
+
+	return reflect.ValueOf(v), nil
+}
+
+func valorui16(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
+	var vacio reflect.Value
+	var v uint16
+
+	if tipoCrudo != nil {
+		if tipoCrudo.String() == "[]uint8" {
+			s, ok := valorCrudo.([]uint8)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
+			}
+			vTemp, err := strconv.ParseUint(string(s), 10, 16)
+			if err != nil {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo uint16")
+			}
+			v = uint16(vTemp)
+		} else {
+			vTemp, ok := valorCrudo.(uint16)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo uint16")
+			}
+			v = vTemp
+		}
+	}
+
+	return reflect.ValueOf(v), nil
+}
+
+func valorui32(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
+	var vacio reflect.Value
+	var v uint32
+
+	if tipoCrudo != nil {
+		if tipoCrudo.String() == "[]uint8" {
+			s, ok := valorCrudo.([]uint8)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
+			}
+			vTemp, err := strconv.ParseUint(string(s), 10, 32)
+			if err != nil {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo uint32")
+			}
+			v = uint32(vTemp)
+		} else {
+			vTemp, ok := valorCrudo.(uint32)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo uint32")
+			}
+			v = vTemp
+		}
+	}
+
+	return reflect.ValueOf(v), nil
+}
+
+func valorui64(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
+	var vacio reflect.Value
+	var v uint64
+
+	if tipoCrudo != nil {
+		if tipoCrudo.String() == "[]uint8" {
+			s, ok := valorCrudo.([]uint8)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
+			}
+			vTemp, err := strconv.ParseUint(string(s), 10, 64)
+			if err != nil {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo uint64")
+			}
+			v = vTemp
+		} else {
+			vTemp, ok := valorCrudo.(uint64)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo uint64")
+			}
+			v = vTemp
+		}
+	}
+
+	return reflect.ValueOf(v), nil
+}
+
+func valorf32(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
+	var vacio reflect.Value
+	var v float32
+
+	if tipoCrudo != nil {
+		if tipoCrudo.String() == "[]uint8" {
+			s, ok := valorCrudo.([]uint8)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
+			}
+			vTemp, err := strconv.ParseFloat(string(s), 32)
+			if err != nil {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo float32")
+			}
+			v = float32(vTemp)
+		} else {
+			vTemp, ok := valorCrudo.(float32)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo float32")
+			}
+			v = float32(vTemp)
+		}
+	}
+
+	return reflect.ValueOf(v), nil
+}
+
+func valorf64(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
+	var vacio reflect.Value
+	var v float64
+
+	if tipoCrudo != nil {
+		if tipoCrudo.String() == "[]uint8" {
+			s, ok := valorCrudo.([]uint8)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
+			}
+			vTemp, err := strconv.ParseFloat(string(s), 64)
+			if err != nil {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo float64")
+			}
+			v = vTemp
+		} else {
+			vTemp, ok := valorCrudo.(float64)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es del tipo float64")
+			}
+			v = vTemp
+		}
+	}
+
+	return reflect.ValueOf(v), nil
+}
+
+func valorbool(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
+	var vacio reflect.Value
+	var v bool
+
+	if tipoCrudo != nil {
+		if tipoCrudo.String() == "[]uint8" {
+			s, ok := valorCrudo.([]uint8)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
+			}
+			vTemp, err := strconv.ParseBool(string(s))
+			if err != nil {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo bool")
+			}
+			v = vTemp
+		} else {
+			switch valorCrudo.(type) {
+			case bool:
+				v = valorCrudo.(bool)
+			case int64:
+				v = valorCrudo == int64(1)
+			default:
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible asignar a tipo bool")
+			}
+		}
+	}
+
+	return reflect.ValueOf(v), nil
+}
+
+func valorstring(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
+	var vacio reflect.Value
+	var v string
+
+	if tipoCrudo != nil {
+		if tipoCrudo.String() == "[]uint8" {
+			s, ok := valorCrudo.([]uint8)
+			if !ok {
+				return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es de tipo uint8[]")
+			}
+			v = fmt.Sprintf("%s", s)
+		} else {
+			v = fmt.Sprintf("%s", valorCrudo)
+		}
+	}
+
+	return reflect.ValueOf(v), nil
+}
+
+func valorFecha(valorCrudo interface{}, tipoCrudo reflect.Type) (reflect.Value, error) {
+	var vacio reflect.Value
+	var v time.Time = time.Time{}
+
+	if valorCrudo != nil {
+		fh, ok := valorCrudo.(time.Time)
+		if !ok {
+			return vacio, errorNuevo().asignarMotivoSeleccionarAsignacionDeCampos("No es posible convertir al tipo time.Time")
+		}
+		v = fh
+	}
+
+	return reflect.ValueOf(v), nil
+}
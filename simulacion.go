@@ -0,0 +1,77 @@
+package bdsql
+
+import (
+	"fmt"
+	"io"
+)
+
+// ModoSimulacion establece que las sentencias de escritura no se ejecuten:
+// se generan y se validan como al ejecutarse y se escriben con sus valores
+// interpolados en la salida de la simulación (por defecto, la salida
+// estándar). Se simulan:
+//
+//   - 'insert', 'update', 'delete' y las restauraciones;
+//   - las sentencias preparadas: no se preparan en el servidor y todas sus
+//     ejecuciones se simulan;
+//   - las sentencias de definición del esquema (CrearTabla, ModificarTabla,
+//     EliminarTabla, CrearIndice);
+//   - las sentencias nativas ejecutadas con ExecContext (de BD, TX o Sesion),
+//     incluidas las de las migraciones.
+//
+// Las consultas ('select') se ejecutan normalmente, al igual que los métodos
+// nativos QueryContext, QueryRowContext y PrepareContext y las operaciones
+// realizadas directamente sobre DB().
+//
+// El resultado de una sentencia simulada informa un registro afectado y
+// ningún id insertado. Se aplica también a las transacciones de la base de
+// datos.
+func (bd *BD) ModoSimulacion(activo bool) *BD {
+	bd.mux.Lock()
+	bd.simulacion = activo
+	bd.mux.Unlock()
+
+	return bd
+}
+
+// SalidaSimulacion establece dónde se escriben las sentencias de escritura
+// en el modo simulación.
+func (bd *BD) SalidaSimulacion(salida io.Writer) *BD {
+	bd.mux.Lock()
+	bd.salidaSimulacion = salida
+	bd.mux.Unlock()
+
+	return bd
+}
+
+// EnSimulacion informa si la base de datos se encuentra en modo simulación
+// (ModoSimulacion).
+func (bd *BD) EnSimulacion() bool {
+	bd.mux.Lock()
+	defer bd.mux.Unlock()
+
+	return bd.simulacion
+}
+
+// simularSentencia escribe la sentencia interpolada en la salida de la
+// simulación y devuelve el resultado simulado.
+func (bd *BD) simularSentencia(sentencia string, valores []interface{}) (Resultado, error) {
+	sentencia, err := bd.interpolarSentencia(sentencia, valores)
+	if err != nil {
+		return Resultado{}, err
+	}
+
+	bd.mux.Lock()
+	defer bd.mux.Unlock()
+	if bd.salidaSimulacion != nil {
+		fmt.Fprintln(bd.salidaSimulacion, sentencia)
+	}
+
+	return Resultado{RegistrosAfectados: 1}, nil
+}
+
+// resultadoSimulado es el resultado (sql.Result) de una sentencia nativa
+// simulada.
+type resultadoSimulado Resultado
+
+func (r resultadoSimulado) LastInsertId() (int64, error) { return r.UltimoID, nil }
+func (r resultadoSimulado) RowsAffected() (int64, error) { return r.RegistrosAfectados, nil }